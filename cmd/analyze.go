@@ -1,78 +1,119 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
 	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 	"github.com/agnivo988/Repo-lyzer/internal/github"
 	"github.com/agnivo988/Repo-lyzer/internal/output"
 )
 
-func RunAnalyze(owner, repo string) error {
-	args := []string{owner + "/" + repo}
-	analyzeCmd.SetArgs(args)
-	return analyzeCmd.Execute()
-}
+var (
+	fastBusFactor  bool
+	truckThreshold float64
+	outputPath     string
+	sectionNames   []string
+)
 
+func init() {
+	analyzeCmd.Flags().BoolVar(&fastBusFactor, "fast-bus-factor", false, "estimate the bus factor from contributor counts instead of file authorship")
+	analyzeCmd.Flags().Float64Var(&truckThreshold, "truck-threshold", analyzer.DefaultTruckThreshold, "share of files that must lose every author for the truck factor")
+	analyzeCmd.Flags().StringVarP(&outputPath, "output", "o", "", "write the report to this file instead of stdout (machine-readable formats only)")
+	analyzeCmd.Flags().StringSliceVar(&sectionNames, "sections", []string{output.SectionAll},
+		"comma-separated sections to print: "+strings.Join(output.SectionNames(), ", ")+" or all; data for the others is not fetched")
+	rootCmd.AddCommand(analyzeCmd)
+}
 
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze owner/repo",
 	Short: "Analyze a GitHub repository",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		parts := strings.Split(args[0], "/")
-		if len(parts) != 2 {
-			return fmt.Errorf("repository must be in owner/repo format")
+		target, err := analysis.ParseTarget(args[0])
+		if err != nil {
+			return err
 		}
-
-		client := github.NewClient()
-		repo, err := client.GetRepo(parts[0], parts[1])
+		if outputPath != "" && format == output.FormatText {
+			return errors.New("--output needs a machine-readable --format; redirect text output instead")
+		}
+		if truckThreshold <= 0 || truckThreshold >= 1 {
+			return fmt.Errorf("--truck-threshold must be between 0 and 1 exclusive, got %g", truckThreshold)
+		}
+		sections, err := output.SelectSections(sectionNames)
 		if err != nil {
 			return err
 		}
 
-		langs, _ := client.GetLanguages(parts[0], parts[1])
-		commits, _ := client.GetCommits(parts[0], parts[1], 365)
-         
-		
-		score := analyzer.CalculateHealth(repo, commits)
-		activity := analyzer.CommitsPerDay(commits)
-		contributors, err := client.GetContributors(parts[0], parts[1])
-            if err != nil {
-	              return err
-                     }
+		ctx, cancel := commandContext(cmd.Context())
+		defer cancel()
 
-					 busFactor, busRisk := analyzer.BusFactor(contributors)
-
-		maturityScore, maturityLevel :=
-			analyzer.RepoMaturityScore(
-				repo,
-				len(commits),
-				len(contributors),
-				false,
-			)
+		profile, err := healthProfile()
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
 
-		summary := analyzer.BuildRecruiterSummary(
-			repo.FullName,
-			repo.Forks,
-		    repo.Stars,
-			len(commits),
-			len(contributors),
-			maturityScore,
-			maturityLevel,
-			busFactor,
-			busRisk,
-		)
+		result, err := analysis.Analyze(ctx, client, target, analysis.Options{
+			Concurrency:    concurrency,
+			FastBusFactor:  fastBusFactor,
+			TruckThreshold: truckThreshold,
+			HealthProfile:  profile,
+			Only:           output.SectionNeeds(sections, profile),
+		})
+		if err != nil {
+			return err
+		}
 
-		output.PrintRepo(repo)
-		output.PrintLanguages(langs)
-		output.PrintCommitActivity(activity,14)
-		output.PrintHealth(score)
-		output.PrintGitHubAPIStatus(client)
-		output.PrintRecruiterSummary(summary)
+		if format != output.FormatText {
+			// Fetch errors are part of the report; warn on stderr too
+			printFetchErrors(os.Stderr, result)
+			return writeReport(output.NewReport(ctx, sections, result, client))
+		}
 
+		printFetchErrors(os.Stdout, result)
+		output.RenderText(ctx, sections, result, client)
 		return nil
 	},
 }
+
+// writeReport encodes the report to --output, or stdout when it is unset.
+func writeReport(report output.Report) error {
+	if outputPath == "" {
+		return output.WriteReport(os.Stdout, report, format)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := output.WriteReport(f, report, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printFetchErrors warns about data that could not be fetched, so gaps in
+// the report are not mistaken for zeros.
+func printFetchErrors(w io.Writer, r *analysis.Result) {
+	for _, f := range analysis.AllFetches {
+		err, ok := r.Errors[f]
+		if !ok {
+			continue
+		}
+		fmt.Fprintln(w, output.WarningStyle.Render(fmt.Sprintf("⚠️ %s: could not fetch %s: %v", r.Target, f, err)))
+		if hint := github.Guidance(err); hint != "" {
+			fmt.Fprintln(w, output.WarningStyle.Render("   💡 "+hint))
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agnivo988/Repo-lyzer/internal/cache"
+	"github.com/agnivo988/Repo-lyzer/internal/config"
+)
+
+var pruneOlderThan time.Duration
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the on-disk GitHub API response cache",
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache size and freshness",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		stats, err := store.Stats()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Location : %s\n", stats.Dir)
+		fmt.Printf("Entries  : %d (%d fresh, %d stale)\n", stats.Entries, stats.Fresh, stats.Stale)
+		fmt.Printf("Size     : %.1f KiB\n", float64(stats.Bytes)/1024)
+		if stats.Entries > 0 {
+			fmt.Printf("Oldest   : %s\n", stats.Oldest.Format(time.DateTime))
+			fmt.Printf("Newest   : %s\n", stats.Newest.Format(time.DateTime))
+		}
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete every cached response",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		removed, err := store.Clear()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d cached responses from %s\n", removed, store.Dir())
+		return nil
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete responses that expired a while ago",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		removed, err := store.Prune(pruneOlderThan)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Pruned %d cached responses expired for more than %s\n", removed, pruneOlderThan)
+		return nil
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().DurationVar(&pruneOlderThan, "older-than", 7*24*time.Hour, "remove entries expired for longer than this")
+
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd, cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func openCache() (*cache.Store, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.OpenCache()
+}
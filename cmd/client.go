@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/config"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+var (
+	// token is set by the --token flag and wins over GITHUB_TOKEN.
+	token string
+	// apiURL is set by the --api-url flag and wins over the config file and
+	// REPOLYZER_API_URL.
+	apiURL string
+	// timeout is set by the --timeout flag; zero means no deadline.
+	timeout time.Duration
+	// concurrency is set by the --concurrency flag.
+	concurrency int
+	// healthProfileSpec is set by the --health-profile flag and wins over
+	// the config file's health_profile.
+	healthProfileSpec string
+)
+
+// commandContext derives the context for a command run: it is cancelled on
+// Ctrl+C and, when --timeout is set, once the deadline passes.
+func commandContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// newClient builds a GitHub client from the config file, environment and
+// command-line flags, in increasing order of precedence.
+func newClient() (*github.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	if apiURL != "" {
+		cfg.APIURL = apiURL
+	}
+	opts := cfg.ClientOptions()
+	if token != "" {
+		opts = append(opts, github.WithToken(token))
+	}
+	return github.NewClient(opts...), nil
+}
+
+// healthProfile loads the health profile named by --health-profile or,
+// failing that, the config file.
+func healthProfile() (analyzer.HealthProfile, error) {
+	spec := healthProfileSpec
+	if spec == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return analyzer.HealthProfile{}, err
+		}
+		spec = cfg.HealthProfile
+	}
+	return config.LoadHealthProfile(spec)
+}
@@ -1,100 +1,106 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"strings"
 
-	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
 
-	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
-	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/config"
+	"github.com/agnivo988/Repo-lyzer/internal/output"
 )
 
-// CompareRepos runs the comparison logic directly
-func CompareRepos(repo1Input, repo2Input string) error {
-	r1 := strings.Split(repo1Input, "/")
-	r2 := strings.Split(repo2Input, "/")
+var (
+	// weights is set by the compare --weights flag and wins over the config
+	// file's compare_weights.
+	weights string
+	// window is set by the compare --window flag.
+	window string
+)
 
-	if len(r1) != 2 || len(r2) != 2 {
-		return fmt.Errorf("repositories must be in owner/repo format")
-	}
+var compareCmd = &cobra.Command{
+	Use:   "compare owner/repo owner/repo...",
+	Short: "Compare two or more GitHub repositories side by side",
+	Long: "Compare two or more GitHub repositories side by side.\n\n" +
+		"Each repository gets a 0-100 score on health, bus factor, maturity, activity,\n" +
+		"issue health and PR health, and is ranked on their weighted mean. Commit\n" +
+		"activity is measured over --window and normalized per week, counting only\n" +
+		"the part of the window each repository existed for.",
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if format != output.FormatText {
+			return fmt.Errorf("compare only supports --format %s", output.FormatText)
+		}
+		ctx, cancel := commandContext(cmd.Context())
+		defer cancel()
+		w, err := analysis.ParseWindow(window)
+		if err != nil {
+			return err
+		}
+		return CompareRepos(ctx, w, args...)
+	},
+}
 
-	client := github.NewClient()
+func init() {
+	compareCmd.Flags().StringVar(&window, "window", analysis.DefaultWindow.Name, "commit window: 30d, 90d, 1y or all")
+	compareCmd.Flags().StringVar(&weights, "weights", "", "dimension weights for the ranking, e.g. health=2,prs=0 (unnamed dimensions weigh 1)")
+	rootCmd.AddCommand(compareCmd)
+}
 
-	// ---------- Fetch Repo 1 ----------
-	repo1, err := client.GetRepo(r1[0], r1[1])
+// compareWeights reads the ranking weights from --weights or, failing
+// that, the config file
+func compareWeights() (analysis.Weights, error) {
+	if weights != "" {
+		return analysis.ParseWeights(weights)
+	}
+	cfg, err := config.Load()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return analysis.WeightsFromMap(cfg.CompareWeights)
+}
 
-	commits1, _ := client.GetCommits(r1[0], r1[1], 14)
-	contributors1, _ := client.GetContributors(r1[0], r1[1])
-	bus1, risk1 := analyzer.BusFactor(contributors1)
-
-	maturityScore1, maturityLevel1 :=
-		analyzer.RepoMaturityScore(repo1, len(commits1), len(contributors1), false)
+// CompareRepos analyzes every repository over the commit window and prints
+// how they rank against each other; ctx bounds every request.
+func CompareRepos(ctx context.Context, window analysis.Window, repoInputs ...string) error {
+	var targets []analysis.Target
+	for _, input := range repoInputs {
+		target, err := analysis.ParseTarget(input)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target)
+	}
 
-	// ---------- Fetch Repo 2 ----------
-	repo2, err := client.GetRepo(r2[0], r2[1])
+	w, err := compareWeights()
+	if err != nil {
+		return err
+	}
+	profile, err := healthProfile()
+	if err != nil {
+		return err
+	}
+	client, err := newClient()
 	if err != nil {
 		return err
 	}
 
-	commits2, _ := client.GetCommits(r2[0], r2[1], 14)
-	contributors2, _ := client.GetContributors(r2[0], r2[1])
-	bus2, risk2 := analyzer.BusFactor(contributors2)
-
-	maturityScore2, maturityLevel2 :=
-		analyzer.RepoMaturityScore(repo2, len(commits2), len(contributors2), false)
-
-	// ---------- Output Table ----------
-	fmt.Println("\n📊 Repository Comparison")
-
-	table := tablewriter.NewWriter(os.Stdout)
-	table.Append([]string{"Metric", repo1.FullName, repo2.FullName})
-
-	table.Append([]string{"⭐ Stars",
-		fmt.Sprintf("%d", repo1.Stars),
-		fmt.Sprintf("%d", repo2.Stars),
-	})
-
-	table.Append([]string{"🍴 Forks",
-		fmt.Sprintf("%d", repo1.Forks),
-		fmt.Sprintf("%d", repo2.Forks),
-	})
-
-	table.Append([]string{"📦 Commits (1y)",
-		fmt.Sprintf("%d", len(commits1)),
-		fmt.Sprintf("%d", len(commits2)),
-	})
-
-	table.Append([]string{"👥 Contributors",
-		fmt.Sprintf("%d", len(contributors1)),
-		fmt.Sprintf("%d", len(contributors2)),
-	})
-
-	table.Append([]string{"⚠️ Bus Factor",
-		fmt.Sprintf("%d (%s)", bus1, risk1),
-		fmt.Sprintf("%d (%s)", bus2, risk2),
-	})
-
-	table.Append([]string{"🏗️ Maturity",
-		fmt.Sprintf("%s (%d)", maturityLevel1, maturityScore1),
-		fmt.Sprintf("%s (%d)", maturityLevel2, maturityScore2),
-	})
-
-	table.Render()
-
-	// ---------- Verdict ----------
-	fmt.Println("\n📌 Verdict")
-	if maturityScore1 > maturityScore2 {
-		fmt.Printf("➡️ %s appears more mature and stable.\n", repo1.FullName)
-	} else if maturityScore2 > maturityScore1 {
-		fmt.Printf("➡️ %s appears more mature and stable.\n", repo2.FullName)
-	} else {
-		fmt.Println("➡️ Both repositories are similarly mature.")
+	// All repositories are fetched together under one concurrency cap
+	opts := analysis.CompareOptions(window, profile)
+	opts.Concurrency = concurrency
+	results := analysis.Run(ctx, client, targets, opts)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err := r.Err(); err != nil {
+			return err
+		}
+		printFetchErrors(os.Stdout, r)
 	}
 
+	output.PrintComparison(analysis.Compare(results, window, w))
 	return nil
 }
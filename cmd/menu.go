@@ -1,15 +1,37 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
+	"github.com/spf13/cobra"
 
 	"github.com/agnivo988/Repo-lyzer/internal/ui"
 )
 
-func RunMenu() {
-	if err := ui.Run(); err != nil {
-		fmt.Println("Error running application:", err)
-		os.Exit(1)
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Open the interactive dashboard",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTUI()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+// runTUI opens the interactive dashboard
+func runTUI() error {
+	w, err := compareWeights()
+	if err != nil {
+		return err
+	}
+	profile, err := healthProfile()
+	if err != nil {
+		return err
+	}
+	client, err := newClient()
+	if err != nil {
+		return err
 	}
+	return ui.Run(client, ui.Options{CompareWeights: w, HealthProfile: profile})
 }
@@ -3,20 +3,64 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/output"
+)
+
+var (
+	// format is set by the --format flag.
+	format string
+	// noColor is set by the --no-color flag; NO_COLOR has the same effect.
+	noColor bool
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "Repo-lyzer",
+	Use:   "repolyzer",
 	Short: "Analyze GitHub repositories from the terminal",
-	Long:  "Repo-lyzer is a fast CLI tool written in Go to analyze GitHub repositories.",
+	Long: "Repo-lyzer is a fast CLI tool written in Go to analyze GitHub repositories.\n\n" +
+		"Run without arguments to open the interactive dashboard.",
+	Args: cobra.NoArgs,
+	// Errors are printed by Execute with guidance attached
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if !output.ValidFormat(format) {
+			return fmt.Errorf("unsupported output format %q (supported: %s)", format, strings.Join(output.Formats, ", "))
+		}
+		if _, ok := os.LookupEnv("NO_COLOR"); ok || noColor {
+			lipgloss.SetColorProfile(termenv.Ascii)
+		}
+		// The arguments were fine; later errors are not usage mistakes
+		cmd.SilenceUsage = true
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTUI()
+	},
+}
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&token, "token", "", "GitHub token (default: $GITHUB_TOKEN)")
+	flags.StringVar(&apiURL, "api-url", "", "GitHub API base URL (e.g. https://ghe.example.com/api/v3/)")
+	flags.StringVar(&format, "format", output.FormatText, "output format: "+strings.Join(output.Formats, ", "))
+	flags.BoolVar(&noColor, "no-color", false, "disable colored output")
+	flags.DurationVar(&timeout, "timeout", 0, "abort the analysis after this long (e.g. 90s, 5m); 0 means no limit")
+	flags.IntVar(&concurrency, "concurrency", analysis.DefaultConcurrency, "maximum number of API requests in flight")
+	flags.StringVar(&healthProfileSpec, "health-profile", "", "health score profile: a preset ("+strings.Join(analyzer.HealthPresetNames(), ", ")+") or a YAML/JSON file")
 }
 
-// Execute is used for cobra commands
+// Execute runs the command named on the command line, or the interactive
+// dashboard when there is none
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		output.PrintError(err)
 		os.Exit(1)
 	}
 }
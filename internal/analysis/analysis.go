@@ -0,0 +1,664 @@
+// Package analysis fetches everything needed to analyze one or more
+// repositories, running independent API calls concurrently, and computes
+// the scores shared by the TUI and the CLI.
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// Fetch names one API call in the fetch graph.
+type Fetch string
+
+const (
+	FetchRepo           Fetch = "repo"
+	FetchCommits        Fetch = "commits"
+	FetchContributors   Fetch = "contributors"
+	FetchLanguages      Fetch = "languages"
+	FetchTree           Fetch = "tree"
+	FetchReleases       Fetch = "releases"
+	FetchTags           Fetch = "tags"
+	FetchIssues         Fetch = "issues"
+	FetchComments       Fetch = "comments"
+	FetchPulls          Fetch = "pulls"
+	FetchPullDetails    Fetch = "pull-details"
+	FetchReviewComments Fetch = "review-comments"
+	FetchCommitFiles    Fetch = "commit-files"
+	FetchBranch         Fetch = "branch"
+	FetchWorkflows      Fetch = "workflows"
+)
+
+// AllFetches lists every fetch in the order they are reported.
+var AllFetches = []Fetch{
+	FetchRepo, FetchCommits, FetchContributors, FetchLanguages, FetchTree,
+	FetchReleases, FetchTags, FetchIssues, FetchComments,
+	FetchPulls, FetchPullDetails, FetchReviewComments, FetchCommitFiles,
+	FetchBranch, FetchWorkflows,
+}
+
+// DefaultConcurrency caps in-flight requests when Options.Concurrency is 0.
+const DefaultConcurrency = 4
+
+// DefaultMaxIssues and DefaultMaxComments bound the issue sample when
+// Options leaves them at 0.
+const (
+	DefaultMaxIssues   = 500
+	DefaultMaxComments = 1000
+	DefaultMaxPulls    = 300
+	DefaultPullSample  = 20
+	DefaultTruckSample = 50
+	maxWorkflows       = 20
+)
+
+// sampleReserve is the core rate budget left untouched by pull request and
+// commit sampling, which cost one or two requests per item.
+const sampleReserve = 20
+
+// ErrSkipped marks a fetch that did not run because one it depends on failed.
+var ErrSkipped = errors.New("skipped")
+
+// Options tunes a run.
+type Options struct {
+	// Concurrency caps in-flight requests across all targets of a run.
+	Concurrency int
+	// CommitsSince is the start of the commit window; zero means one year ago.
+	CommitsSince time.Time
+	// MaxCommits caps how many commits are fetched; zero means no cap.
+	MaxCommits int
+	// MaxIssues caps how many of the newest issues are analyzed.
+	MaxIssues int
+	// MaxComments caps how many issue comments are read for response times.
+	MaxComments int
+	// MaxPulls caps how many of the newest pull requests are analyzed.
+	MaxPulls int
+	// PullSample is how many of the newest closed pull requests get their
+	// size and reviews fetched.
+	PullSample int
+	// TruckSample is how many commits, spread over the commit window, get
+	// their file lists fetched for the truck factor.
+	TruckSample int
+	// TruckThreshold is the share of files that must be orphaned; zero
+	// means analyzer.DefaultTruckThreshold.
+	TruckThreshold float64
+	// HealthProfile scores health; a profile without rules means the
+	// default preset. Add HealthFetches to Only for every rule to be
+	// checked.
+	HealthProfile analyzer.HealthProfile
+	// FastBusFactor skips the commit file lists and uses the contributor
+	// heuristic instead of the truck factor.
+	FastBusFactor bool
+	// Only restricts the run to these fetches (plus what they depend on);
+	// nil fetches everything.
+	Only []Fetch
+	// Progress, if set, receives an Event as each fetch starts, reads a
+	// page and finishes. It is called from worker goroutines and must not
+	// block for long.
+	Progress func(Event)
+}
+
+// Result is the data fetched for one target plus the scores derived from it.
+type Result struct {
+	Target Target
+
+	Repo           *github.Repo
+	Commits        []github.Commit
+	Contributors   []github.Contributor
+	Languages      map[string]int
+	FileTree       []github.TreeEntry
+	TreeTruncated  bool
+	Releases       []github.Release
+	Tags           []github.Tag
+	Issues         []github.Issue
+	IssueComments  []github.IssueComment
+	Pulls          []github.PullRequest
+	PullDetails    map[int]github.PullRequest
+	PullReviews    map[int][]github.Review
+	ReviewComments []github.ReviewComment
+	CommitFiles    []github.Commit // sampled commits with their file lists
+	Branch         *github.Branch  // the default branch
+	Workflows      map[string][]byte
+	// IssueCommentsCapped and ReviewCommentsCapped are set when a comment
+	// listing hit MaxComments and so misses the oldest comments
+	IssueCommentsCapped  bool
+	ReviewCommentsCapped bool
+
+	HealthScore   int
+	Health        analyzer.HealthReport
+	BusFactor     int
+	BusRisk       string
+	TruckFactor   analyzer.TruckFactor
+	MaturityScore int
+	MaturityLevel string
+	Maturity      analyzer.Maturity
+	ReleaseStats  analyzer.ReleaseStats
+	IssueHealth   analyzer.IssueHealth
+	PRHealth      analyzer.PRHealth
+	Security      analyzer.SecurityReport
+	Files         analyzer.FileStats
+
+	// Errors holds the fetches that failed; the rest of the result is still
+	// usable unless FetchRepo is among them.
+	Errors map[Fetch]error
+
+	mu sync.Mutex
+}
+
+// Err returns the error that makes the result unusable: a failed
+// repository fetch. Failures of other fetches only leave gaps.
+func (r *Result) Err() error {
+	if err := r.Errors[FetchRepo]; err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", r.Target, err)
+	}
+	return nil
+}
+
+func (r *Result) setErr(f Fetch, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Errors[f] = err
+}
+
+func (r *Result) failed(f Fetch) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Errors[f] != nil
+}
+
+// Analyze runs a single target. The error is non-nil when the repository
+// itself could not be fetched or ctx was cancelled.
+func Analyze(ctx context.Context, client *github.Client, target Target, opts Options) (*Result, error) {
+	results := Run(ctx, client, []Target{target}, opts)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := results[0].Err(); err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// Run analyzes every target at once. All targets share one concurrency
+// cap, so comparing repositories costs no more parallelism than analyzing
+// one. Results are returned in target order; check Result.Err for each.
+func Run(ctx context.Context, client *github.Client, targets []Target, opts Options) []*Result {
+	limit := opts.Concurrency
+	if limit <= 0 {
+		limit = DefaultConcurrency
+	}
+	sem := make(chan struct{}, limit)
+
+	results := make([]*Result, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		results[i] = &Result{Target: target, Errors: map[Fetch]error{}}
+		runGraph(ctx, client, results[i], opts, sem, &wg)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Err() == nil {
+			r.computeScores(opts)
+		}
+	}
+	return results
+}
+
+// task is a node of the fetch graph.
+type task struct {
+	fetch Fetch
+	deps  []Fetch
+	run   func(ctx context.Context, c *github.Client, r *Result, opts Options) error
+}
+
+// graph describes what each fetch needs. The tree and branch are addressed
+// by the repository's default branch, workflows are found in the tree,
+// comments are read from the creation of the
+// oldest sampled issue or pull request onwards, and pull request details are
+// fetched for a sample of the listed pull requests.
+var graph = []task{
+	{fetch: FetchRepo, run: fetchRepo},
+	{fetch: FetchCommits, run: fetchCommits},
+	{fetch: FetchContributors, run: fetchContributors},
+	{fetch: FetchLanguages, run: fetchLanguages},
+	{fetch: FetchTree, deps: []Fetch{FetchRepo}, run: fetchTree},
+	{fetch: FetchReleases, run: fetchReleases},
+	{fetch: FetchTags, run: fetchTags},
+	{fetch: FetchIssues, run: fetchIssues},
+	{fetch: FetchComments, deps: []Fetch{FetchIssues}, run: fetchComments},
+	{fetch: FetchPulls, run: fetchPulls},
+	{fetch: FetchPullDetails, deps: []Fetch{FetchPulls}, run: fetchPullDetails},
+	{fetch: FetchReviewComments, deps: []Fetch{FetchPulls}, run: fetchReviewComments},
+	{fetch: FetchCommitFiles, deps: []Fetch{FetchCommits}, run: fetchCommitFiles},
+	{fetch: FetchBranch, deps: []Fetch{FetchRepo}, run: fetchBranch},
+	{fetch: FetchWorkflows, deps: []Fetch{FetchTree}, run: fetchWorkflows},
+}
+
+// ConcatFetches joins fetch lists, dropping repeats. The result is never
+// nil, so an empty join still restricts Options.Only to nothing extra.
+func ConcatFetches(lists ...[]Fetch) []Fetch {
+	seen := map[Fetch]bool{}
+	joined := []Fetch{}
+	for _, list := range lists {
+		for _, f := range list {
+			if !seen[f] {
+				seen[f] = true
+				joined = append(joined, f)
+			}
+		}
+	}
+	return joined
+}
+
+// selected reports which fetches to run for opts, always including the
+// repository because every score needs it. FastBusFactor drops the commit
+// file lists.
+func selected(opts Options) map[Fetch]bool {
+	want := map[Fetch]bool{FetchRepo: true}
+	if opts.Only == nil {
+		for _, t := range graph {
+			want[t.fetch] = true
+		}
+	}
+	for _, f := range opts.Only {
+		want[f] = true
+	}
+	for _, t := range graph {
+		if want[t.fetch] {
+			for _, dep := range t.deps {
+				want[dep] = true
+			}
+		}
+	}
+	if opts.FastBusFactor {
+		delete(want, FetchCommitFiles)
+	}
+	return want
+}
+
+// Fetches lists the fetches a run with these options makes, in AllFetches
+// order.
+func (o Options) Fetches() []Fetch {
+	want := selected(o)
+	var fetches []Fetch
+	for _, f := range AllFetches {
+		if want[f] {
+			fetches = append(fetches, f)
+		}
+	}
+	return fetches
+}
+
+// runGraph starts one goroutine per selected task. Each waits for its
+// dependencies, then for a slot in sem.
+func runGraph(ctx context.Context, client *github.Client, r *Result, opts Options, sem chan struct{}, wg *sync.WaitGroup) {
+	want := selected(opts)
+	done := make(map[Fetch]chan struct{}, len(graph))
+	for _, t := range graph {
+		if want[t.fetch] {
+			done[t.fetch] = make(chan struct{})
+		}
+	}
+
+	for _, t := range graph {
+		if !want[t.fetch] {
+			continue
+		}
+
+		wg.Add(1)
+		go func(t task) {
+			defer wg.Done()
+			defer close(done[t.fetch])
+
+			report := newStageReporter(opts, r.Target, t.fetch)
+			fail := func(err error) {
+				r.setErr(t.fetch, err)
+				report.send(StageFinished, err)
+			}
+
+			for _, dep := range t.deps {
+				<-done[dep]
+				if r.failed(dep) {
+					fail(fmt.Errorf("%w: %s failed", ErrSkipped, dep))
+					return
+				}
+			}
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				fail(ctx.Err())
+				return
+			}
+			report.send(StageStarted, nil)
+			err := t.run(report.observe(ctx), client, r, opts)
+			<-sem
+
+			if err != nil {
+				fail(err)
+				return
+			}
+			report.send(StageFinished, nil)
+		}(t)
+	}
+}
+
+func fetchRepo(ctx context.Context, c *github.Client, r *Result, _ Options) error {
+	repo, err := c.GetRepo(ctx, r.Target.Owner, r.Target.Name)
+	if err != nil {
+		return err
+	}
+	r.Repo = repo
+	return nil
+}
+
+func fetchCommits(ctx context.Context, c *github.Client, r *Result, opts Options) error {
+	since := opts.CommitsSince
+	if since.IsZero() {
+		since = time.Now().AddDate(-1, 0, 0)
+	}
+
+	commits, err := c.ListCommits(ctx, r.Target.Owner, r.Target.Name, github.CommitOptions{
+		Since:    since,
+		MaxCount: opts.MaxCommits,
+	})
+	r.Commits = commits
+	if errors.Is(err, github.ErrEmptyRepository) {
+		// No commits is a valid answer, not a failure
+		return nil
+	}
+	return err
+}
+
+func fetchContributors(ctx context.Context, c *github.Client, r *Result, _ Options) error {
+	contributors, err := c.GetContributors(ctx, r.Target.Owner, r.Target.Name)
+	r.Contributors = contributors
+	return err
+}
+
+func fetchLanguages(ctx context.Context, c *github.Client, r *Result, _ Options) error {
+	languages, err := c.GetLanguages(ctx, r.Target.Owner, r.Target.Name)
+	r.Languages = languages
+	return err
+}
+
+func fetchTree(ctx context.Context, c *github.Client, r *Result, _ Options) error {
+	tree, err := c.GetFileTree(ctx, r.Target.Owner, r.Target.Name, r.Repo.DefaultBranch)
+	r.FileTree = tree
+	if errors.Is(err, github.ErrTruncatedTree) {
+		r.TreeTruncated = true
+		return nil
+	}
+	return err
+}
+
+func fetchReleases(ctx context.Context, c *github.Client, r *Result, _ Options) error {
+	releases, err := c.GetReleases(ctx, r.Target.Owner, r.Target.Name)
+	r.Releases = releases
+	return err
+}
+
+func fetchTags(ctx context.Context, c *github.Client, r *Result, _ Options) error {
+	tags, err := c.GetTags(ctx, r.Target.Owner, r.Target.Name)
+	r.Tags = tags
+	return err
+}
+
+func fetchIssues(ctx context.Context, c *github.Client, r *Result, opts Options) error {
+	max := opts.MaxIssues
+	if max <= 0 {
+		max = DefaultMaxIssues
+	}
+	issues, err := c.GetIssues(ctx, r.Target.Owner, r.Target.Name, github.IssueOptions{MaxCount: max})
+	r.Issues = issues
+	return err
+}
+
+func fetchComments(ctx context.Context, c *github.Client, r *Result, opts Options) error {
+	if len(r.Issues) == 0 {
+		return nil
+	}
+	since := r.Issues[0].CreatedAt
+	for _, issue := range r.Issues {
+		if issue.CreatedAt.Before(since) {
+			since = issue.CreatedAt
+		}
+	}
+
+	max := opts.MaxComments
+	if max <= 0 {
+		max = DefaultMaxComments
+	}
+	comments, err := c.GetIssueComments(ctx, r.Target.Owner, r.Target.Name, since, max)
+	r.IssueComments = comments
+	r.IssueCommentsCapped = len(comments) >= max
+	return err
+}
+
+func fetchPulls(ctx context.Context, c *github.Client, r *Result, opts Options) error {
+	max := opts.MaxPulls
+	if max <= 0 {
+		max = DefaultMaxPulls
+	}
+	pulls, err := c.GetPullRequests(ctx, r.Target.Owner, r.Target.Name, github.PullRequestOptions{MaxCount: max})
+	r.Pulls = pulls
+	return err
+}
+
+// fetchPullDetails fetches size and reviews for the newest closed pull
+// requests. It stops early rather than spend the last of the rate budget.
+func fetchPullDetails(ctx context.Context, c *github.Client, r *Result, opts Options) error {
+	sample := opts.PullSample
+	if sample <= 0 {
+		sample = DefaultPullSample
+	}
+
+	details := map[int]github.PullRequest{}
+	reviews := map[int][]github.Review{}
+	defer func() {
+		r.PullDetails = details
+		r.PullReviews = reviews
+	}()
+
+	for _, pr := range r.Pulls {
+		if len(reviews) == sample {
+			break
+		}
+		if pr.State != "closed" {
+			continue
+		}
+		if budget, ok := c.RateBudget(); ok && budget.Resources.Core.Remaining < sampleReserve {
+			break
+		}
+
+		full, err := c.GetPullRequest(ctx, r.Target.Owner, r.Target.Name, pr.Number)
+		if err != nil {
+			return err
+		}
+		rs, err := c.GetPullRequestReviews(ctx, r.Target.Owner, r.Target.Name, pr.Number)
+		if err != nil {
+			return err
+		}
+		details[pr.Number] = *full
+		reviews[pr.Number] = rs
+	}
+	return nil
+}
+
+func fetchReviewComments(ctx context.Context, c *github.Client, r *Result, opts Options) error {
+	if len(r.Pulls) == 0 {
+		return nil
+	}
+	since := r.Pulls[0].CreatedAt
+	for _, pr := range r.Pulls {
+		if pr.CreatedAt.Before(since) {
+			since = pr.CreatedAt
+		}
+	}
+
+	max := opts.MaxComments
+	if max <= 0 {
+		max = DefaultMaxComments
+	}
+	comments, err := c.GetReviewComments(ctx, r.Target.Owner, r.Target.Name, since, max)
+	r.ReviewComments = comments
+	r.ReviewCommentsCapped = len(comments) >= max
+	return err
+}
+
+// fetchCommitFiles fetches the file lists of commits spread evenly over
+// the commit window, skipping merges. Like pull request sampling it stops
+// early rather than spend the last of the rate budget.
+func fetchCommitFiles(ctx context.Context, c *github.Client, r *Result, opts Options) error {
+	sample := opts.TruckSample
+	if sample <= 0 {
+		sample = DefaultTruckSample
+	}
+
+	var candidates []github.Commit
+	for _, commit := range r.Commits {
+		if !commit.IsMerge() {
+			candidates = append(candidates, commit)
+		}
+	}
+	step := 1.0
+	if len(candidates) > sample {
+		step = float64(len(candidates)) / float64(sample)
+	}
+
+	var detailed []github.Commit
+	defer func() { r.CommitFiles = detailed }()
+
+	for i := 0.0; int(i) < len(candidates); i += step {
+		if budget, ok := c.RateBudget(); ok && budget.Resources.Core.Remaining < sampleReserve {
+			break
+		}
+		commit, err := c.GetCommit(ctx, r.Target.Owner, r.Target.Name, candidates[int(i)].SHA)
+		if err != nil {
+			return err
+		}
+		detailed = append(detailed, *commit)
+	}
+	return nil
+}
+
+func fetchBranch(ctx context.Context, c *github.Client, r *Result, _ Options) error {
+	branch, err := c.GetBranch(ctx, r.Target.Owner, r.Target.Name, r.Repo.DefaultBranch)
+	r.Branch = branch
+	return err
+}
+
+// fetchWorkflows reads the GitHub Actions workflows listed in the tree.
+func fetchWorkflows(ctx context.Context, c *github.Client, r *Result, _ Options) error {
+	workflows := map[string][]byte{}
+	for i, p := range analyzer.WorkflowPaths(r.FileTree) {
+		if i == maxWorkflows {
+			break
+		}
+		content, err := c.GetContents(ctx, r.Target.Owner, r.Target.Name, p, r.Repo.DefaultBranch)
+		if err != nil {
+			return err
+		}
+		data, err := content.Decode()
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		workflows[p] = data
+	}
+	r.Workflows = workflows
+	return nil
+}
+
+// computeScores derives the scores from whatever was fetched.
+func (r *Result) computeScores(opts Options) {
+	r.ReleaseStats = analyzer.AnalyzeReleases(r.Releases, r.Tags, time.Now())
+	want := selected(opts)
+	// Security tells commits not fetched (nil) from none in the window
+	commits := r.Commits
+	if commits == nil && r.have(want, FetchCommits) {
+		commits = []github.Commit{}
+	}
+	r.Security = analyzer.AnalyzeSecurity(r.FileTree, commits, r.Branch, r.Workflows)
+	r.IssueHealth = analyzer.AnalyzeIssues(r.Issues, r.IssueComments, r.have(want, FetchComments) && !r.IssueCommentsCapped)
+	r.PRHealth = analyzer.AnalyzePullRequests(r.Pulls, r.PullDetails, r.PullReviews, r.ReviewComments, r.have(want, FetchReviewComments) && !r.ReviewCommentsCapped)
+	r.Files = analyzer.AnalyzeFiles(r.FileTree)
+	if len(r.CommitFiles) > 0 {
+		r.TruckFactor = analyzer.CalculateTruckFactor(r.CommitFiles, r.FileTree, opts.TruckThreshold)
+	}
+	if r.TruckFactor.FilesAnalyzed == 0 {
+		r.TruckFactor = analyzer.TruckFactorHeuristic(r.Contributors)
+	}
+	r.BusFactor, r.BusRisk = r.TruckFactor.Value, r.TruckFactor.Risk
+	r.Health = analyzer.EvaluateHealth(healthProfile(opts), r.healthInput(selected(opts)))
+	r.HealthScore = r.Health.Score
+	r.Maturity = analyzer.AssessMaturity(r.maturityInput(selected(opts)))
+	r.MaturityScore, r.MaturityLevel = r.Maturity.Score, r.Maturity.Level
+}
+
+// have reports whether every fetch was asked for and succeeded
+func (r *Result) have(want map[Fetch]bool, fetches ...Fetch) bool {
+	for _, f := range fetches {
+		if !want[f] || r.failed(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// healthInput hands the health rules what was fetched, leaving out data
+// that was not asked for or failed so their rules are skipped.
+func (r *Result) healthInput(want map[Fetch]bool) analyzer.HealthInput {
+	have := func(fetches ...Fetch) bool { return r.have(want, fetches...) }
+
+	in := analyzer.HealthInput{Repo: r.Repo}
+	if have(FetchCommits) {
+		commits := len(r.Commits)
+		in.Commits = &commits
+	}
+	if have(FetchContributors) {
+		contributors := len(r.Contributors)
+		in.Contributors = &contributors
+	}
+	if have(FetchTree) {
+		in.Files = &r.Files
+		in.Security = &r.Security
+	}
+	if have(FetchReleases, FetchTags) {
+		in.Releases = &r.ReleaseStats
+	}
+	if have(FetchIssues) {
+		in.Issues = &r.IssueHealth
+	}
+	if have(FetchPulls) {
+		in.Pulls = &r.PRHealth
+	}
+	if have(FetchContributors) || have(FetchCommitFiles) {
+		in.TruckFactor = &r.TruckFactor
+	}
+	return in
+}
+
+// maturityInput hands the maturity signals what was fetched, leaving out
+// data that was not asked for or failed so confidence drops instead.
+func (r *Result) maturityInput(want map[Fetch]bool) analyzer.MaturityInput {
+	in := analyzer.MaturityInput{Repo: r.Repo}
+	if r.have(want, FetchCommits) {
+		in.Commits = r.Commits
+	}
+	if r.have(want, FetchReleases, FetchTags) {
+		in.Releases = &r.ReleaseStats
+	}
+	if r.have(want, FetchIssues) {
+		in.Issues = &r.IssueHealth
+	}
+	if r.have(want, FetchTree) {
+		in.Files = &r.Files
+	}
+	return in
+}
@@ -0,0 +1,210 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// fakeGitHub answers every endpoint a run uses with a minimal repository:
+// one commit, no issues, pull requests or releases, and an empty tree.
+// Paths listed in fail answer 500. Every request path is recorded.
+type fakeGitHub struct {
+	fail  map[string]bool
+	delay time.Duration
+
+	mu       sync.Mutex
+	paths    []string
+	inFlight int32
+	peak     int32
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	f.mu.Lock()
+	f.paths = append(f.paths, r.URL.Path)
+	f.peak = max(f.peak, n)
+	f.mu.Unlock()
+	time.Sleep(f.delay)
+
+	p := r.URL.Path
+	repo := p[:min(len(p), len("/repos/o/r"))]
+	rest := strings.TrimPrefix(p, repo)
+	switch {
+	case f.fail[rest]:
+		http.Error(w, `{"message":"boom"}`, http.StatusInternalServerError)
+	case rest == "":
+		w.Write([]byte(`{"full_name":"o/r","default_branch":"main","created_at":"2020-01-01T00:00:00Z"}`))
+	case rest == "/languages":
+		w.Write([]byte(`{"Go":100}`))
+	case strings.HasPrefix(rest, "/git/trees/"):
+		w.Write([]byte(`{"sha":"t","tree":[]}`))
+	case strings.HasPrefix(rest, "/branches/"):
+		w.Write([]byte(`{"name":"main","protected":false}`))
+	case strings.HasPrefix(rest, "/commits/"):
+		w.Write([]byte(`{"sha":"c1","commit":{"author":{"name":"a","date":"2026-01-01T00:00:00Z"}},"files":[{"filename":"a.go","status":"added"}]}`))
+	case rest == "/commits":
+		w.Write([]byte(`[{"sha":"c1","commit":{"author":{"name":"a","date":"2026-01-01T00:00:00Z"}}}]`))
+	default:
+		w.Write([]byte(`[]`))
+	}
+}
+
+func (f *fakeGitHub) requested(suffix string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range f.paths {
+		if strings.HasSuffix(p, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func runFake(t *testing.T, f *fakeGitHub, targets []Target, opts Options) []*Result {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client := github.NewClient(github.WithBaseURL(srv.URL), github.WithToken(""), github.WithRetryPolicy(github.RetryPolicy{}))
+	return Run(context.Background(), client, targets, opts)
+}
+
+var fakeTarget = Target{Owner: "o", Name: "r"}
+
+func TestRunSkipsDependentsOfFailedFetches(t *testing.T) {
+	f := &fakeGitHub{fail: map[string]bool{"/pulls": true}}
+	r := runFake(t, f, []Target{fakeTarget}, Options{})[0]
+
+	if r.Err() != nil {
+		t.Fatalf("Err = %v", r.Err())
+	}
+	if err := r.Errors[FetchPulls]; err == nil || errors.Is(err, ErrSkipped) {
+		t.Errorf("pulls error = %v, want the request failure", err)
+	}
+	for _, f := range []Fetch{FetchPullDetails, FetchReviewComments} {
+		if !errors.Is(r.Errors[f], ErrSkipped) {
+			t.Errorf("%s error = %v, want ErrSkipped", f, r.Errors[f])
+		}
+	}
+	// Fetches not depending on pull requests still ran
+	for _, f := range []Fetch{FetchIssues, FetchComments, FetchCommits, FetchTree} {
+		if err := r.Errors[f]; err != nil {
+			t.Errorf("%s error = %v", f, err)
+		}
+	}
+}
+
+func TestRunFailedRepositorySkipsItsDependents(t *testing.T) {
+	f := &fakeGitHub{fail: map[string]bool{"": true}}
+	r := runFake(t, f, []Target{fakeTarget}, Options{Only: []Fetch{FetchWorkflows, FetchBranch}})[0]
+	if r.Err() == nil {
+		t.Fatal("Err = nil with the repository failed")
+	}
+	for _, f := range []Fetch{FetchTree, FetchBranch, FetchWorkflows} {
+		if !errors.Is(r.Errors[f], ErrSkipped) {
+			t.Errorf("%s error = %v, want ErrSkipped", f, r.Errors[f])
+		}
+	}
+	if f.requested("/branches/main") || f.requested("/git/trees/main") {
+		t.Error("dependents of the failed repository made requests")
+	}
+}
+
+func TestOptionsFetches(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []Fetch
+	}{
+		{"only repository", Options{Only: []Fetch{}}, []Fetch{FetchRepo}},
+		{"workflows pull in the tree", Options{Only: []Fetch{FetchWorkflows}}, []Fetch{FetchRepo, FetchTree, FetchWorkflows}},
+		{"comments pull in issues", Options{Only: []Fetch{FetchComments}}, []Fetch{FetchRepo, FetchIssues, FetchComments}},
+		{"review comments pull in pulls", Options{Only: []Fetch{FetchReviewComments, FetchLanguages}}, []Fetch{FetchRepo, FetchLanguages, FetchPulls, FetchReviewComments}},
+		{"commit files pull in commits", Options{Only: []Fetch{FetchCommitFiles}}, []Fetch{FetchRepo, FetchCommits, FetchCommitFiles}},
+		{"fast bus factor drops commit files", Options{Only: []Fetch{FetchCommitFiles}, FastBusFactor: true}, []Fetch{FetchRepo, FetchCommits}},
+		{"everything", Options{}, AllFetches},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.Fetches(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fetches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	fast := Options{FastBusFactor: true}.Fetches()
+	for _, f := range fast {
+		if f == FetchCommitFiles {
+			t.Error("FastBusFactor still fetches commit files")
+		}
+	}
+	if len(fast) != len(AllFetches)-1 {
+		t.Errorf("FastBusFactor fetches %v", fast)
+	}
+}
+
+func TestRunOnlyRequestsSelectedFetches(t *testing.T) {
+	f := &fakeGitHub{}
+	r := runFake(t, f, []Target{fakeTarget}, Options{Only: []Fetch{FetchWorkflows}})[0]
+	if r.Err() != nil {
+		t.Fatal(r.Err())
+	}
+	if !f.requested("/git/trees/main") {
+		t.Error("the tree workflows depend on was not fetched")
+	}
+	for _, p := range []string{"/commits", "/issues", "/pulls", "/languages", "/branches/main"} {
+		if f.requested(p) {
+			t.Errorf("%s was requested though not selected", p)
+		}
+	}
+	if _, ok := r.Errors[FetchCommits]; ok {
+		t.Error("unselected fetch recorded an outcome")
+	}
+}
+
+func TestRunFastBusFactorSkipsCommitFiles(t *testing.T) {
+	for _, fast := range []bool{false, true} {
+		f := &fakeGitHub{}
+		r := runFake(t, f, []Target{fakeTarget}, Options{Only: []Fetch{FetchCommitFiles}, FastBusFactor: fast})[0]
+		if r.Err() != nil {
+			t.Fatal(r.Err())
+		}
+		if got := f.requested("/commits/c1"); got == fast {
+			t.Errorf("FastBusFactor %v: commit file list requested = %v", fast, got)
+		}
+		if !f.requested("/commits") {
+			t.Errorf("FastBusFactor %v: commits were not listed", fast)
+		}
+		if fast && len(r.CommitFiles) != 0 {
+			t.Errorf("FastBusFactor kept %d commit file lists", len(r.CommitFiles))
+		}
+	}
+}
+
+func TestRunConcurrencyCap(t *testing.T) {
+	for _, limit := range []int{1, 3} {
+		f := &fakeGitHub{delay: 5 * time.Millisecond}
+		targets := []Target{{Owner: "o", Name: "r"}, {Owner: "o", Name: "s"}}
+		for _, r := range runFake(t, f, targets, Options{Concurrency: limit}) {
+			if r.Err() != nil {
+				t.Fatal(r.Err())
+			}
+		}
+		if f.peak > int32(limit) {
+			t.Errorf("Concurrency %d: %d requests in flight", limit, f.peak)
+		}
+		if limit > 1 && f.peak < 2 {
+			t.Errorf("Concurrency %d: requests never overlapped", limit)
+		}
+	}
+}
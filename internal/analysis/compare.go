@@ -0,0 +1,383 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dimension is one axis of the radar score a comparison ranks repositories
+// on.
+type Dimension string
+
+const (
+	DimHealth   Dimension = "health"
+	DimBus      Dimension = "bus"
+	DimMaturity Dimension = "maturity"
+	DimActivity Dimension = "activity"
+	DimIssues   Dimension = "issues"
+	DimPulls    Dimension = "prs"
+)
+
+// Dimensions lists every dimension in display order.
+var Dimensions = []Dimension{DimHealth, DimBus, DimMaturity, DimActivity, DimIssues, DimPulls}
+
+// Label is the dimension's display name.
+func (d Dimension) Label() string {
+	switch d {
+	case DimHealth:
+		return "Health"
+	case DimBus:
+		return "Bus factor"
+	case DimMaturity:
+		return "Maturity"
+	case DimActivity:
+		return "Activity"
+	case DimIssues:
+		return "Issue health"
+	case DimPulls:
+		return "PR health"
+	}
+	return string(d)
+}
+
+// CompareFetches is what a comparison needs fetched for every dimension to
+// be scored.
+var CompareFetches = []Fetch{
+	FetchCommits, FetchContributors, FetchTree, FetchCommitFiles,
+	FetchReleases, FetchTags,
+	FetchIssues, FetchComments,
+	FetchPulls, FetchPullDetails, FetchReviewComments,
+}
+
+// Weights sets how much each dimension counts towards the overall score.
+// Dimensions left out count zero.
+type Weights map[Dimension]float64
+
+// DefaultWeights counts every dimension equally.
+func DefaultWeights() Weights {
+	w := Weights{}
+	for _, d := range Dimensions {
+		w[d] = 1
+	}
+	return w
+}
+
+// ParseWeights reads "health=2,bus=1.5" on top of DefaultWeights, so only
+// the dimensions that should differ need naming.
+func ParseWeights(s string) (Weights, error) {
+	w := DefaultWeights()
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("weight %q must be dimension=number", part)
+		}
+		d := Dimension(strings.ToLower(strings.TrimSpace(name)))
+		if _, known := w[d]; !known {
+			return nil, fmt.Errorf("unknown dimension %q (available: %s)", name, dimensionNames())
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("weight for %s must be a non-negative number, got %q", d, value)
+		}
+		w[d] = f
+	}
+	return w, w.validate()
+}
+
+// WeightsFromMap converts weights keyed by dimension name, as read from the
+// config file, on top of DefaultWeights.
+func WeightsFromMap(m map[string]float64) (Weights, error) {
+	w := DefaultWeights()
+	for name, f := range m {
+		d := Dimension(strings.ToLower(name))
+		if _, known := w[d]; !known {
+			return nil, fmt.Errorf("unknown dimension %q (available: %s)", name, dimensionNames())
+		}
+		if f < 0 {
+			return nil, fmt.Errorf("weight for %s must not be negative", d)
+		}
+		w[d] = f
+	}
+	return w, w.validate()
+}
+
+func (w Weights) validate() error {
+	for _, f := range w {
+		if f > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("at least one weight must be positive")
+}
+
+// String lists the weights in dimension order, e.g. "health=2 bus=1".
+func (w Weights) String() string {
+	parts := make([]string, 0, len(Dimensions))
+	for _, d := range Dimensions {
+		parts = append(parts, fmt.Sprintf("%s=%s", d, strconv.FormatFloat(w[d], 'g', -1, 64)))
+	}
+	return strings.Join(parts, " ")
+}
+
+func dimensionNames() string {
+	names := make([]string, len(Dimensions))
+	for i, d := range Dimensions {
+		names[i] = string(d)
+	}
+	return strings.Join(names, ", ")
+}
+
+// CompareMetric is one row of the comparison matrix. Leaders holds the
+// indexes of the repositories with the best value; it is empty when the
+// values all tie or none are known.
+type CompareMetric struct {
+	Name    string
+	Labels  []string  // display value per repository, "-" when unknown
+	Values  []float64 // raw value per repository; higher is better
+	Known   []bool
+	Leaders []int
+}
+
+// RepoScore is one repository's normalized scores. Scores holds 0-100 per
+// dimension, leaving out dimensions without data, and Overall is their
+// weighted mean.
+type RepoScore struct {
+	Repo    string
+	Scores  map[Dimension]float64
+	Overall float64
+	Rank    int // 1 is best; repositories with equal overall scores share a rank
+}
+
+// Comparison ranks a set of analyzed repositories against each other over
+// one commit window.
+type Comparison struct {
+	Repos    []string
+	Window   Window
+	Activity []WindowActivity // in Repos order
+	Metrics  []CompareMetric
+	Scores   []RepoScore // in Repos order
+	Ranking  []int       // indexes into Repos, best first
+	Weights  Weights
+}
+
+// Compare builds the matrix, radar scores and ranking for results, which
+// must all have their repository fetched with CompareOptions(window).
+// Scores already on a 0-100 scale are used as they are; bus factor and
+// commits per week are scaled against the best repository in the set.
+func Compare(results []*Result, window Window, weights Weights) Comparison {
+	if weights == nil {
+		weights = DefaultWeights()
+	}
+	c := Comparison{Window: window, Weights: weights}
+	now := time.Now()
+	for _, r := range results {
+		c.Repos = append(c.Repos, r.Repo.FullName)
+		c.Activity = append(c.Activity, windowActivity(r, window, now))
+	}
+	activity := map[*Result]WindowActivity{}
+	for i, r := range results {
+		activity[r] = c.Activity[i]
+	}
+
+	metric := func(name string, value func(r *Result) (float64, string, bool)) CompareMetric {
+		m := CompareMetric{Name: name}
+		for _, r := range results {
+			v, label, ok := value(r)
+			if !ok {
+				v, label = 0, "-"
+			}
+			m.Values = append(m.Values, v)
+			m.Labels = append(m.Labels, label)
+			m.Known = append(m.Known, ok)
+		}
+		m.Leaders = leaders(m.Values, m.Known)
+		return m
+	}
+	count := func(n int) string { return strconv.Itoa(n) }
+
+	c.Metrics = []CompareMetric{
+		metric("Stars", func(r *Result) (float64, string, bool) {
+			return float64(r.Repo.Stars), count(r.Repo.Stars), true
+		}),
+		metric("Forks", func(r *Result) (float64, string, bool) {
+			return float64(r.Repo.Forks), count(r.Repo.Forks), true
+		}),
+		metric("Commits", func(r *Result) (float64, string, bool) {
+			label := count(len(r.Commits))
+			if activity[r].Truncated {
+				label += "+"
+			}
+			return float64(len(r.Commits)), label, !r.failed(FetchCommits)
+		}),
+		metric("Commits/week", func(r *Result) (float64, string, bool) {
+			a := activity[r]
+			return a.CommitsPerWeek, rounded(a.CommitsPerWeek), !r.failed(FetchCommits)
+		}),
+		metric("Active days", func(r *Result) (float64, string, bool) {
+			a := activity[r]
+			return a.ActiveShare, fmt.Sprintf("%d/%d (%s%%)", a.ActiveDays, a.Days, rounded(a.ActiveShare*100)), !r.failed(FetchCommits)
+		}),
+		metric("Contributors", func(r *Result) (float64, string, bool) {
+			return float64(len(r.Contributors)), count(len(r.Contributors)), !r.failed(FetchContributors)
+		}),
+		metric("Health", func(r *Result) (float64, string, bool) {
+			return float64(r.HealthScore), count(r.HealthScore), r.Health.Known()
+		}),
+		metric("Bus factor", func(r *Result) (float64, string, bool) {
+			return float64(r.BusFactor), fmt.Sprintf("%d (%s)", r.BusFactor, r.BusRisk), r.BusFactor > 0
+		}),
+		metric("Maturity", func(r *Result) (float64, string, bool) {
+			return float64(r.MaturityScore), fmt.Sprintf("%s (%d)", r.MaturityLevel, r.MaturityScore), r.Maturity.Confidence > 0
+		}),
+		metric("Issue health", func(r *Result) (float64, string, bool) {
+			h := r.IssueHealth
+			return float64(h.Score), fmt.Sprintf("%s (%d)", h.Grade, h.Score), h.Total > 0
+		}),
+		metric("PR health", func(r *Result) (float64, string, bool) {
+			h := r.PRHealth
+			return float64(h.Score), fmt.Sprintf("%s (%d)", h.Grade, h.Score), h.Total > 0
+		}),
+	}
+
+	// The highest bus factor and commit rate in the set score 100
+	maxBus, maxRate := 0.0, 0.0
+	for _, r := range results {
+		maxBus = max(maxBus, float64(r.BusFactor))
+		if !r.failed(FetchCommits) {
+			maxRate = max(maxRate, activity[r].CommitsPerWeek)
+		}
+	}
+
+	for _, r := range results {
+		s := RepoScore{Repo: r.Repo.FullName, Scores: map[Dimension]float64{}}
+		if r.Health.Known() {
+			s.Scores[DimHealth] = float64(r.HealthScore)
+		}
+		if !r.failed(FetchCommits) {
+			s.Scores[DimActivity] = relative(activity[r].CommitsPerWeek, maxRate)
+		}
+		if r.Maturity.Confidence > 0 {
+			s.Scores[DimMaturity] = float64(r.MaturityScore)
+		}
+		if r.BusFactor > 0 {
+			s.Scores[DimBus] = relative(float64(r.BusFactor), maxBus)
+		}
+		if r.IssueHealth.Total > 0 {
+			s.Scores[DimIssues] = float64(r.IssueHealth.Score)
+		}
+		if r.PRHealth.Total > 0 {
+			s.Scores[DimPulls] = float64(r.PRHealth.Score)
+		}
+		s.Overall = weights.overall(s.Scores)
+		c.Scores = append(c.Scores, s)
+	}
+
+	c.Ranking = make([]int, len(c.Scores))
+	for i := range c.Ranking {
+		c.Ranking[i] = i
+	}
+	sort.SliceStable(c.Ranking, func(a, b int) bool {
+		return c.Scores[c.Ranking[a]].Overall > c.Scores[c.Ranking[b]].Overall
+	})
+	for pos, i := range c.Ranking {
+		rank := pos + 1
+		if pos > 0 {
+			prev := c.Scores[c.Ranking[pos-1]]
+			if roundScore(prev.Overall) == roundScore(c.Scores[i].Overall) {
+				rank = prev.Rank
+			}
+		}
+		c.Scores[i].Rank = rank
+	}
+	return c
+}
+
+// Leader is the best ranked repository, or "" when the top of the ranking
+// is shared.
+func (c Comparison) Leader() string {
+	if len(c.Ranking) == 0 {
+		return ""
+	}
+	if len(c.Ranking) > 1 && c.Scores[c.Ranking[1]].Rank == 1 {
+		return ""
+	}
+	return c.Repos[c.Ranking[0]]
+}
+
+// IsLeader reports whether repository i leads the metric.
+func (m CompareMetric) IsLeader(i int) bool {
+	for _, l := range m.Leaders {
+		if l == i {
+			return true
+		}
+	}
+	return false
+}
+
+// overall is the weighted mean of the known scores. Dimensions without
+// data are left out and the remaining weights rescaled, so a repository
+// without issues is not punished for it.
+func (w Weights) overall(scores map[Dimension]float64) float64 {
+	var sum, total float64
+	for _, d := range Dimensions {
+		if score, ok := scores[d]; ok && w[d] > 0 {
+			sum += score * w[d]
+			total += w[d]
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// leaders finds the best known values. Nobody leads when they all tie.
+func leaders(values []float64, known []bool) []int {
+	best, seen := 0.0, 0
+	for i, v := range values {
+		if known[i] {
+			if seen == 0 || v > best {
+				best = v
+			}
+			seen++
+		}
+	}
+	var top []int
+	for i, v := range values {
+		if known[i] && v == best {
+			top = append(top, i)
+		}
+	}
+	if len(top) == seen && seen > 1 {
+		return nil
+	}
+	return top
+}
+
+// relative scales n against the best in the set to 0-100.
+func relative(n, best float64) float64 {
+	if best == 0 {
+		return 0
+	}
+	return n / best * 100
+}
+
+// rounded formats a rate, keeping two places for small ones so slow
+// repositories do not all read as zero
+func rounded(f float64) string {
+	if f < 10 {
+		return strconv.FormatFloat(f, 'f', 2, 64)
+	}
+	return strconv.FormatFloat(f, 'f', 1, 64)
+}
+
+func roundScore(f float64) int {
+	return int(f + 0.5)
+}
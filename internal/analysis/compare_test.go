@@ -0,0 +1,266 @@
+package analysis
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// compareResult is a fetched repository with nothing else known about it
+func compareResult(name string) *Result {
+	return &Result{
+		Repo:   &github.Repo{FullName: name, CreatedAt: time.Now().AddDate(-1, 0, 0)},
+		Errors: map[Fetch]error{},
+	}
+}
+
+func healthReport(score int, skipped ...bool) analyzer.HealthReport {
+	report := analyzer.HealthReport{Score: score}
+	for _, s := range skipped {
+		report.Checks = append(report.Checks, analyzer.HealthCheck{Skipped: s})
+	}
+	return report
+}
+
+func findMetric(t *testing.T, c Comparison, name string) CompareMetric {
+	t.Helper()
+	for _, m := range c.Metrics {
+		if m.Name == name {
+			return m
+		}
+	}
+	t.Fatalf("no %q metric", name)
+	return CompareMetric{}
+}
+
+func TestCompareHealthKnownFromChecks(t *testing.T) {
+	scored := compareResult("o/scored")
+	scored.Health, scored.HealthScore = healthReport(80, true, false), 80
+
+	// Commits failed but the community files were still checked
+	noCommits := compareResult("o/no-commits")
+	noCommits.Errors[FetchCommits] = errors.New("boom")
+	noCommits.Health, noCommits.HealthScore = healthReport(60, true, false), 60
+
+	// Commits fetched but every rule lacked data
+	unchecked := compareResult("o/unchecked")
+	unchecked.Health = healthReport(0, true, true)
+
+	c := Compare([]*Result{scored, noCommits, unchecked}, Window{}, nil)
+
+	health := findMetric(t, c, "Health")
+	if want := []bool{true, true, false}; !reflect.DeepEqual(health.Known, want) {
+		t.Errorf("Known = %v, want %v", health.Known, want)
+	}
+	if health.Labels[2] != "-" {
+		t.Errorf("unchecked label = %q, want -", health.Labels[2])
+	}
+	if got, ok := c.Scores[1].Scores[DimHealth]; !ok || got != 60 {
+		t.Errorf("health without commits scored %v, %v, want 60", got, ok)
+	}
+	if _, ok := c.Scores[1].Scores[DimActivity]; ok {
+		t.Error("activity scored without commits")
+	}
+	if _, ok := c.Scores[2].Scores[DimHealth]; ok {
+		t.Error("health scored with every check skipped")
+	}
+}
+
+func TestLeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		known  []bool
+		want   []int
+	}{
+		{"single best", []float64{1, 3, 2}, []bool{true, true, true}, []int{1}},
+		{"shared best", []float64{3, 1, 3}, []bool{true, true, true}, []int{0, 2}},
+		{"all tie", []float64{2, 2, 2}, []bool{true, true, true}, nil},
+		{"unknown values are ignored", []float64{0, 5, 1}, []bool{true, false, true}, []int{2}},
+		{"unknown zero does not tie", []float64{0, 0}, []bool{true, false}, []int{0}},
+		{"only one known", []float64{0, 4}, []bool{false, true}, []int{1}},
+		{"none known", []float64{1, 2}, []bool{false, false}, nil},
+		{"all zero", []float64{0, 0}, []bool{true, true}, nil},
+		{"negative values", []float64{-3, -1}, []bool{true, true}, []int{1}},
+		{"single repository", []float64{7}, []bool{true}, []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leaders(tt.values, tt.known); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("leaders = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelative(t *testing.T) {
+	tests := []struct{ n, best, want float64 }{
+		{5, 10, 50},
+		{10, 10, 100},
+		{0, 10, 0},
+		{0, 0, 0},
+		{3, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := relative(tt.n, tt.best); got != tt.want {
+			t.Errorf("relative(%v, %v) = %v, want %v", tt.n, tt.best, got, tt.want)
+		}
+	}
+}
+
+func TestWeightsOverall(t *testing.T) {
+	scores := map[Dimension]float64{DimHealth: 80, DimBus: 40}
+	tests := []struct {
+		name    string
+		weights Weights
+		want    float64
+	}{
+		{"equal weights use known dimensions only", DefaultWeights(), 60},
+		{"weights are normalized", Weights{DimHealth: 3, DimBus: 1}, 70},
+		{"scaling every weight changes nothing", Weights{DimHealth: 30, DimBus: 10}, 70},
+		{"zero weight drops a dimension", Weights{DimHealth: 0, DimBus: 1}, 40},
+		{"weight on an unknown dimension is ignored", Weights{DimHealth: 1, DimIssues: 5}, 80},
+		{"no weighted dimension known", Weights{DimIssues: 1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.weights.overall(scores); got != tt.want {
+				t.Errorf("overall = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := DefaultWeights().overall(nil); got != 0 {
+		t.Errorf("overall without scores = %v, want 0", got)
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    map[Dimension]float64 // differences from the defaults
+		wantErr string
+	}{
+		{in: "", want: nil},
+		{in: "health=2, bus=0.5", want: map[Dimension]float64{DimHealth: 2, DimBus: 0.5}},
+		{in: "PRS=0,", want: map[Dimension]float64{DimPulls: 0}},
+		{in: "stars=1", wantErr: "unknown dimension"},
+		{in: "health", wantErr: "must be dimension=number"},
+		{in: "health=-1", wantErr: "non-negative"},
+		{in: "health=lots", wantErr: "non-negative"},
+		{in: "health=0,bus=0,maturity=0,activity=0,issues=0,prs=0", wantErr: "at least one weight"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseWeights(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := DefaultWeights()
+			for d, f := range tt.want {
+				want[d] = f
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWeightsFromMap(t *testing.T) {
+	w, err := WeightsFromMap(map[string]float64{"Health": 2})
+	if err != nil || w[DimHealth] != 2 || w[DimBus] != 1 {
+		t.Errorf("got %v, %v", w, err)
+	}
+	if _, err := WeightsFromMap(map[string]float64{"stars": 1}); err == nil {
+		t.Error("unknown dimension accepted")
+	}
+	if _, err := WeightsFromMap(map[string]float64{"bus": -1}); err == nil {
+		t.Error("negative weight accepted")
+	}
+}
+
+func TestCompareRanking(t *testing.T) {
+	withScores := func(name string, health, issues int) *Result {
+		r := compareResult(name)
+		r.Health, r.HealthScore = healthReport(health, false), health
+		if issues >= 0 {
+			r.IssueHealth = analyzer.IssueHealth{Total: 1, Score: issues}
+		}
+		return r
+	}
+
+	t.Run("ties share a rank", func(t *testing.T) {
+		c := Compare([]*Result{
+			withScores("o/a", 50, -1),
+			withScores("o/b", 90, -1),
+			withScores("o/c", 50, -1),
+			withScores("o/d", 10, -1),
+		}, Window{}, Weights{DimHealth: 1})
+		if want := []int{1, 0, 2, 3}; !reflect.DeepEqual(c.Ranking, want) {
+			t.Errorf("Ranking = %v, want %v", c.Ranking, want)
+		}
+		ranks := []int{c.Scores[0].Rank, c.Scores[1].Rank, c.Scores[2].Rank, c.Scores[3].Rank}
+		if want := []int{2, 1, 2, 4}; !reflect.DeepEqual(ranks, want) {
+			t.Errorf("ranks = %v, want %v", ranks, want)
+		}
+		if c.Leader() != "o/b" {
+			t.Errorf("Leader = %q, want o/b", c.Leader())
+		}
+	})
+
+	t.Run("shared first place has no leader", func(t *testing.T) {
+		c := Compare([]*Result{withScores("o/a", 70, -1), withScores("o/b", 70, -1)}, Window{}, Weights{DimHealth: 1})
+		if c.Scores[0].Rank != 1 || c.Scores[1].Rank != 1 {
+			t.Errorf("ranks = %d, %d, want 1, 1", c.Scores[0].Rank, c.Scores[1].Rank)
+		}
+		if c.Leader() != "" {
+			t.Errorf("Leader = %q, want none", c.Leader())
+		}
+		if health := findMetric(t, c, "Health"); health.Leaders != nil {
+			t.Errorf("Health leaders = %v, want none", health.Leaders)
+		}
+	})
+
+	t.Run("unknown dimensions are not held against a repository", func(t *testing.T) {
+		// b has no issues; its overall is its health alone
+		c := Compare([]*Result{withScores("o/a", 80, 20), withScores("o/b", 60, -1)}, Window{}, Weights{DimHealth: 1, DimIssues: 1})
+		if c.Scores[0].Overall != 50 || c.Scores[1].Overall != 60 {
+			t.Errorf("overall = %v, %v, want 50, 60", c.Scores[0].Overall, c.Scores[1].Overall)
+		}
+		if c.Leader() != "o/b" {
+			t.Errorf("Leader = %q, want o/b", c.Leader())
+		}
+		issues := findMetric(t, c, "Issue health")
+		if issues.Known[1] || issues.Labels[1] != "-" || !issues.IsLeader(0) {
+			t.Errorf("issue metric = %+v", issues)
+		}
+	})
+
+	t.Run("scores within rounding share a rank", func(t *testing.T) {
+		// 70 against 69.5, both shown as 70
+		c := Compare([]*Result{withScores("o/a", 70, -1), withScores("o/b", 69, 70)}, Window{}, Weights{DimHealth: 1, DimIssues: 1})
+		if c.Scores[0].Rank != 1 || c.Scores[1].Rank != 1 {
+			t.Errorf("ranks = %d, %d, want 1, 1", c.Scores[0].Rank, c.Scores[1].Rank)
+		}
+	})
+
+	t.Run("bus factor is scaled to the best", func(t *testing.T) {
+		a, b := compareResult("o/a"), compareResult("o/b")
+		a.BusFactor, b.BusFactor = 4, 2
+		c := Compare([]*Result{a, b}, Window{}, Weights{DimBus: 1})
+		if c.Scores[0].Scores[DimBus] != 100 || c.Scores[1].Scores[DimBus] != 50 {
+			t.Errorf("bus scores = %v, %v", c.Scores[0].Scores, c.Scores[1].Scores)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package analysis
+
+import "github.com/agnivo988/Repo-lyzer/internal/analyzer"
+
+// healthRuleFetches is the data each health rule reads; rules missing here
+// only need the repository.
+var healthRuleFetches = map[string][]Fetch{
+	"recent_commits": {FetchCommits},
+	"contributors":   {FetchContributors},
+	"readme":         {FetchTree},
+	"license":        {FetchTree},
+	"contributing":   {FetchTree},
+	"ci":             {FetchTree},
+	"tests":          {FetchTree},
+	"releases":       {FetchReleases, FetchTags},
+	"recent_release": {FetchReleases, FetchTags},
+	"issue_health":   {FetchIssues, FetchComments},
+	"pr_health":      {FetchPulls, FetchPullDetails, FetchReviewComments},
+	"security":       {FetchTree, FetchCommits, FetchBranch, FetchWorkflows},
+	"bus_factor":     {FetchCommits, FetchContributors, FetchTree, FetchCommitFiles},
+}
+
+// HealthFetches lists what the profile's rules read, for Options.Only.
+func HealthFetches(profile analyzer.HealthProfile) []Fetch {
+	var fetches []Fetch
+	for _, rule := range defaultHealthProfile(profile).Rules {
+		fetches = ConcatFetches(fetches, healthRuleFetches[rule.Rule])
+	}
+	return fetches
+}
+
+// healthProfile is the profile a run scores health with
+func healthProfile(opts Options) analyzer.HealthProfile {
+	return defaultHealthProfile(opts.HealthProfile)
+}
+
+func defaultHealthProfile(p analyzer.HealthProfile) analyzer.HealthProfile {
+	if len(p.Rules) == 0 {
+		p, _ = analyzer.HealthPreset(analyzer.DefaultHealthProfile)
+	}
+	return p
+}
@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// EventKind says what an Event reports.
+type EventKind int
+
+const (
+	StageStarted EventKind = iota
+	PageFetched
+	StageFinished
+)
+
+// Event is one progress update from a run. Pages and Bytes are running
+// totals for the stage; Err is set on a StageFinished that failed.
+type Event struct {
+	Target Target
+	Kind   EventKind
+	Stage  Fetch
+	Pages  int
+	Bytes  int64
+	Err    error
+	Time   time.Time
+}
+
+// stageReporter emits the events of one fetch.
+type stageReporter struct {
+	emit   func(Event)
+	target Target
+	stage  Fetch
+	pages  atomic.Int64
+	bytes  atomic.Int64
+}
+
+func newStageReporter(opts Options, target Target, stage Fetch) *stageReporter {
+	return &stageReporter{emit: opts.Progress, target: target, stage: stage}
+}
+
+func (s *stageReporter) send(kind EventKind, err error) {
+	if s.emit == nil {
+		return
+	}
+	s.emit(Event{
+		Target: s.target,
+		Kind:   kind,
+		Stage:  s.stage,
+		Pages:  int(s.pages.Load()),
+		Bytes:  s.bytes.Load(),
+		Err:    err,
+		Time:   time.Now(),
+	})
+}
+
+// observe wires the reporter into every request made with the returned ctx.
+func (s *stageReporter) observe(ctx context.Context) context.Context {
+	if s.emit == nil {
+		return ctx
+	}
+	return github.WithPageObserver(ctx, func(p github.PageInfo) {
+		s.pages.Add(1)
+		s.bytes.Add(p.Bytes)
+		s.send(PageFetched, nil)
+	})
+}
@@ -0,0 +1,25 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Target identifies a repository to analyze.
+type Target struct {
+	Owner string
+	Name  string
+}
+
+// ParseTarget parses "owner/repo".
+func ParseTarget(s string) (Target, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Target{}, fmt.Errorf("repository must be in owner/repo format, got %q", s)
+	}
+	return Target{Owner: parts[0], Name: parts[1]}, nil
+}
+
+func (t Target) String() string {
+	return t.Owner + "/" + t.Name
+}
@@ -0,0 +1,135 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+)
+
+// Window is the span of commit history a comparison looks at. Days is zero
+// for the whole history.
+type Window struct {
+	Name string
+	Days int
+}
+
+// Windows lists the supported windows, shortest first.
+var Windows = []Window{
+	{Name: "30d", Days: 30},
+	{Name: "90d", Days: 90},
+	{Name: "1y", Days: 365},
+	{Name: "all"},
+}
+
+// DefaultWindow is the window used unless one is chosen.
+var DefaultWindow = Windows[2]
+
+// maxHistoryCommits caps the commits fetched for the "all" window; older
+// history is left out and the window shortened to what was fetched.
+const maxHistoryCommits = 5000
+
+// ParseWindow looks up a window by name: 30d, 90d, 1y or all.
+func ParseWindow(s string) (Window, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, w := range Windows {
+		if w.Name == s {
+			return w, nil
+		}
+	}
+	return Window{}, fmt.Errorf("unknown window %q (available: %s)", s, windowNames())
+}
+
+// Label describes the window for headings, e.g. "last 90 days".
+func (w Window) Label() string {
+	switch {
+	case w.Days == 0:
+		return "all history"
+	case w.Days == 365:
+		return "last year"
+	}
+	return fmt.Sprintf("last %d days", w.Days)
+}
+
+// Next is the window after w in Windows, wrapping around.
+func (w Window) Next() Window {
+	for i, candidate := range Windows {
+		if candidate == w {
+			return Windows[(i+1)%len(Windows)]
+		}
+	}
+	return DefaultWindow
+}
+
+// CompareOptions is the run every comparison makes, so the CLI and the TUI
+// score repositories from the same data. Callers add Concurrency and
+// Progress.
+func CompareOptions(w Window, profile analyzer.HealthProfile) Options {
+	opts := Options{
+		Only:          ConcatFetches(CompareFetches, HealthFetches(profile)),
+		HealthProfile: profile,
+	}
+	if w.Days == 0 {
+		// Reach back to the first commit, however old the imported history
+		opts.CommitsSince = time.Unix(0, 0).UTC()
+		opts.MaxCommits = maxHistoryCommits
+	} else {
+		opts.CommitsSince = time.Now().AddDate(0, 0, -w.Days)
+	}
+	return opts
+}
+
+func windowNames() string {
+	names := make([]string, len(Windows))
+	for i, w := range Windows {
+		names[i] = w.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// WindowActivity is a repository's commit activity normalized over the part
+// of the window it existed for, so young repositories are not measured
+// against time before they were created.
+type WindowActivity struct {
+	Start          time.Time // the later of the window start and the repository's creation
+	Days           int       // days from Start to now, at least one
+	Commits        int
+	CommitsPerWeek float64
+	ActiveDays     int     // days with at least one commit
+	ActiveShare    float64 // ActiveDays over Days, 0-1
+	Truncated      bool    // history was capped, so Start is the oldest commit fetched
+}
+
+// windowActivity measures r's commits over w as of now.
+func windowActivity(r *Result, w Window, now time.Time) WindowActivity {
+	a := WindowActivity{Commits: len(r.Commits)}
+
+	// Imported history can predate the repository on GitHub
+	var oldest time.Time
+	for _, commit := range r.Commits {
+		if date := commit.Commit.Author.Date; oldest.IsZero() || date.Before(oldest) {
+			oldest = date
+		}
+	}
+	start := r.Repo.CreatedAt
+	if !oldest.IsZero() && oldest.Before(start) {
+		start = oldest
+	}
+
+	switch {
+	case w.Days > 0:
+		if windowStart := now.AddDate(0, 0, -w.Days); windowStart.After(start) {
+			start = windowStart
+		}
+	case len(r.Commits) >= maxHistoryCommits:
+		start, a.Truncated = oldest, true
+	}
+
+	a.Start = start
+	a.Days = max(int(now.Sub(start).Hours()/24), 1)
+	a.ActiveDays = len(analyzer.CommitsPerDay(r.Commits))
+	a.CommitsPerWeek = float64(a.Commits) / float64(a.Days) * 7
+	a.ActiveShare = min(float64(a.ActiveDays)/float64(a.Days), 1)
+	return a
+}
@@ -2,6 +2,8 @@ package analyzer
 
 import "github.com/agnivo988/Repo-lyzer/internal/github"
 
+// BusFactor is the fast heuristic: it only looks at how much of the commit
+// history the top contributor owns, so it never exceeds 3
 func BusFactor(contributors []github.Contributor) (int, string) {
 	if len(contributors) == 0 {
 		return 0, "Unknown"
@@ -1 +1,350 @@
-package analyzer
\ No newline at end of file
+package analyzer
+
+import (
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// ExtensionStats is the file count and size of one extension
+type ExtensionStats struct {
+	Ext   string // "(none)" for files without an extension
+	Files int
+	Bytes int64
+}
+
+// DirectoryStats is the file count and size under one top-level directory
+type DirectoryStats struct {
+	Dir   string // "." for files at the root
+	Files int
+	Bytes int64
+}
+
+// LanguageTests compares test files to source files for one language
+type LanguageTests struct {
+	Language string
+	Source   int
+	Tests    int
+	Ratio    float64 // tests per source file
+}
+
+// CommunityFile records whether a standard project file is present
+type CommunityFile struct {
+	Name string
+	Path string // where it was found, "" when missing
+}
+
+// Present reports whether the file was found
+func (c CommunityFile) Present() bool {
+	return c.Path != ""
+}
+
+// FileInfo is a single file and its size
+type FileInfo struct {
+	Path string
+	Size int
+}
+
+// FileStats describes a repository's structure
+type FileStats struct {
+	Files       int
+	Dirs        int
+	Bytes       int64
+	Extensions  []ExtensionStats // largest first
+	Directories []DirectoryStats // largest first
+	Tests       []LanguageTests
+	Community   []CommunityFile
+	Largest     []FileInfo
+	Vendored    []string // vendored or generated directories
+	Binaries    []FileInfo
+	MaxDepth    int
+	DeepPaths   []string // files nested far deeper than the rest
+}
+
+// TestFiles is the total number of test files across languages
+func (s FileStats) TestFiles() int {
+	n := 0
+	for _, t := range s.Tests {
+		n += t.Tests
+	}
+	return n
+}
+
+// HasCommunityFile reports whether the named community file was found
+func (s FileStats) HasCommunityFile(name string) bool {
+	for _, c := range s.Community {
+		if c.Name == name {
+			return c.Present()
+		}
+	}
+	return false
+}
+
+// Community file names
+const (
+	FileReadme        = "README"
+	FileLicense       = "LICENSE"
+	FileContributing  = "CONTRIBUTING"
+	FileCodeOfConduct = "CODE_OF_CONDUCT"
+	FileChangelog     = "CHANGELOG"
+	FileCI            = "CI config"
+)
+
+// communityPrefixes lists, per community file, the base names accepted in
+// the root, .github/ or docs/, with any extension. Dashed names such as
+// LICENSE-MIT are accepted unless exact is set; changelog names are too
+// generic for that.
+var communityPrefixes = []struct {
+	name     string
+	prefixes []string
+	exact    bool
+}{
+	{FileReadme, []string{"readme"}, false},
+	{FileLicense, []string{"license", "licence", "copying"}, false},
+	{FileContributing, []string{"contributing"}, false},
+	{FileCodeOfConduct, []string{"code_of_conduct", "code-of-conduct"}, false},
+	{FileChangelog, []string{"changelog", "changes"}, true},
+}
+
+// ciFiles are CI configurations other than GitHub Actions workflows
+var ciFiles = map[string]bool{
+	".gitlab-ci.yml": true, ".travis.yml": true, ".circleci/config.yml": true,
+	"azure-pipelines.yml": true, "Jenkinsfile": true, ".drone.yml": true,
+	"appveyor.yml": true, "bitbucket-pipelines.yml": true, ".buildkite/pipeline.yml": true,
+}
+
+// vendoredDirs are directory names holding third-party or generated code
+var vendoredDirs = map[string]bool{
+	"vendor": true, "node_modules": true, "third_party": true, "thirdparty": true,
+	"external": true, "bower_components": true, "Pods": true,
+	"dist": true, "generated": true, "gen": true, "__generated__": true,
+}
+
+// binaryExts are compiled artifacts and archives rather than source
+var binaryExts = map[string]bool{
+	".exe": true, ".dll": true, ".so": true, ".dylib": true, ".bin": true, ".o": true,
+	".a": true, ".lib": true, ".class": true, ".jar": true, ".war": true, ".pyc": true,
+	".zip": true, ".tar": true, ".gz": true, ".tgz": true, ".7z": true, ".rar": true,
+	".iso": true, ".dmg": true, ".msi": true, ".deb": true, ".rpm": true, ".apk": true,
+}
+
+// sourceLanguages maps source extensions to a language
+var sourceLanguages = map[string]string{
+	".go": "Go", ".py": "Python", ".js": "JavaScript", ".jsx": "JavaScript", ".mjs": "JavaScript",
+	".ts": "TypeScript", ".tsx": "TypeScript", ".java": "Java", ".kt": "Kotlin", ".rb": "Ruby",
+	".rs": "Rust", ".c": "C", ".h": "C", ".cpp": "C++", ".cc": "C++", ".hpp": "C++",
+	".cs": "C#", ".php": "PHP", ".swift": "Swift", ".scala": "Scala", ".ex": "Elixir", ".exs": "Elixir",
+	".dart": "Dart",
+}
+
+// testDirs are path segments that hold tests
+var testDirs = map[string]bool{"test": true, "tests": true, "__tests__": true, "spec": true, "specs": true, "testing": true}
+
+// AnalyzeFiles summarises the structure of a tree as returned by GetFileTree
+func AnalyzeFiles(tree []github.TreeEntry) FileStats {
+	var s FileStats
+
+	exts := map[string]*ExtensionStats{}
+	dirs := map[string]*DirectoryStats{}
+	tests := map[string]*LanguageTests{}
+	found := map[string]string{}
+	vendored := map[string]bool{}
+	var files []FileInfo
+	var depths []int
+
+	for _, e := range tree {
+		if e.Type == "tree" {
+			s.Dirs++
+			if vendoredDirs[path.Base(e.Path)] && !underVendored(e.Path, vendored) {
+				vendored[e.Path] = true
+			}
+			continue
+		}
+		if e.Type != "blob" {
+			continue
+		}
+
+		s.Files++
+		s.Bytes += int64(e.Size)
+		files = append(files, FileInfo{Path: e.Path, Size: e.Size})
+
+		ext := strings.ToLower(path.Ext(e.Path))
+		key := ext
+		if key == "" {
+			key = "(none)"
+		}
+		if exts[key] == nil {
+			exts[key] = &ExtensionStats{Ext: key}
+		}
+		exts[key].Files++
+		exts[key].Bytes += int64(e.Size)
+
+		top := "."
+		if i := strings.Index(e.Path, "/"); i >= 0 {
+			top = e.Path[:i]
+		}
+		if dirs[top] == nil {
+			dirs[top] = &DirectoryStats{Dir: top}
+		}
+		dirs[top].Files++
+		dirs[top].Bytes += int64(e.Size)
+
+		depth := strings.Count(e.Path, "/")
+		depths = append(depths, depth)
+		if depth > s.MaxDepth {
+			s.MaxDepth = depth
+		}
+
+		if binaryExts[ext] {
+			s.Binaries = append(s.Binaries, FileInfo{Path: e.Path, Size: e.Size})
+		}
+
+		if lang, ok := sourceLanguages[ext]; ok && !underVendored(e.Path, vendored) {
+			if tests[lang] == nil {
+				tests[lang] = &LanguageTests{Language: lang}
+			}
+			if isTestFile(e.Path) {
+				tests[lang].Tests++
+			} else {
+				tests[lang].Source++
+			}
+		}
+
+		if name := communityFile(e.Path); name != "" && found[name] == "" {
+			found[name] = e.Path
+		}
+	}
+
+	for _, ext := range exts {
+		s.Extensions = append(s.Extensions, *ext)
+	}
+	sort.Slice(s.Extensions, func(i, j int) bool {
+		if s.Extensions[i].Bytes != s.Extensions[j].Bytes {
+			return s.Extensions[i].Bytes > s.Extensions[j].Bytes
+		}
+		return s.Extensions[i].Ext < s.Extensions[j].Ext
+	})
+
+	for _, d := range dirs {
+		s.Directories = append(s.Directories, *d)
+	}
+	sort.Slice(s.Directories, func(i, j int) bool {
+		if s.Directories[i].Bytes != s.Directories[j].Bytes {
+			return s.Directories[i].Bytes > s.Directories[j].Bytes
+		}
+		return s.Directories[i].Dir < s.Directories[j].Dir
+	})
+
+	for _, t := range tests {
+		if t.Source > 0 {
+			t.Ratio = float64(t.Tests) / float64(t.Source)
+		}
+		s.Tests = append(s.Tests, *t)
+	}
+	sort.Slice(s.Tests, func(i, j int) bool {
+		a, b := s.Tests[i].Source+s.Tests[i].Tests, s.Tests[j].Source+s.Tests[j].Tests
+		if a != b {
+			return a > b
+		}
+		return s.Tests[i].Language < s.Tests[j].Language
+	})
+
+	for _, c := range communityPrefixes {
+		s.Community = append(s.Community, CommunityFile{Name: c.name, Path: found[c.name]})
+	}
+	s.Community = append(s.Community, CommunityFile{Name: FileCI, Path: found[FileCI]})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+	if len(files) > 10 {
+		files = files[:10]
+	}
+	s.Largest = files
+
+	for dir := range vendored {
+		s.Vendored = append(s.Vendored, dir)
+	}
+	sort.Strings(s.Vendored)
+
+	s.DeepPaths = deepOutliers(tree, depths)
+	return s
+}
+
+// underVendored reports whether p lies inside an already recorded vendored directory
+func underVendored(p string, vendored map[string]bool) bool {
+	for dir := range vendored {
+		if strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestFile recognises test files by naming convention or directory
+func isTestFile(p string) bool {
+	name := path.Base(p)
+	stem := strings.TrimSuffix(name, path.Ext(name))
+	lower := strings.ToLower(stem)
+	switch {
+	case strings.HasSuffix(lower, "_test"), strings.HasSuffix(lower, "_spec"),
+		strings.HasPrefix(lower, "test_"),
+		strings.Contains(name, ".test."), strings.Contains(name, ".spec."),
+		// FooTest.java, FooTests.cs
+		len(stem) > 4 && (strings.HasSuffix(stem, "Test") || strings.HasSuffix(stem, "Tests")):
+		return true
+	}
+	for _, segment := range strings.Split(path.Dir(p), "/") {
+		if testDirs[strings.ToLower(segment)] {
+			return true
+		}
+	}
+	return false
+}
+
+// communityFile returns which community file p is, if any
+func communityFile(p string) string {
+	if ciFiles[p] || (strings.HasPrefix(p, ".github/workflows/") && (strings.HasSuffix(p, ".yml") || strings.HasSuffix(p, ".yaml"))) {
+		return FileCI
+	}
+
+	dir := path.Dir(p)
+	if dir != "." && dir != ".github" && dir != "docs" {
+		return ""
+	}
+	name := strings.ToLower(path.Base(p))
+	for _, c := range communityPrefixes {
+		for _, prefix := range c.prefixes {
+			if name == prefix || strings.HasPrefix(name, prefix+".") || (!c.exact && strings.HasPrefix(name, prefix+"-")) {
+				return c.name
+			}
+		}
+	}
+	return ""
+}
+
+// deepOutliers lists files nested more than two standard deviations deeper
+// than average, and at least six levels down
+func deepOutliers(tree []github.TreeEntry, depths []int) []string {
+	if len(depths) == 0 {
+		return nil
+	}
+	var sum, sq float64
+	for _, d := range depths {
+		sum += float64(d)
+	}
+	mean := sum / float64(len(depths))
+	for _, d := range depths {
+		sq += (float64(d) - mean) * (float64(d) - mean)
+	}
+	limit := math.Max(mean+2*math.Sqrt(sq/float64(len(depths))), 6)
+
+	var deep []string
+	for _, e := range tree {
+		if e.Type == "blob" && float64(strings.Count(e.Path, "/")) > limit {
+			deep = append(deep, e.Path)
+		}
+	}
+	return deep
+}
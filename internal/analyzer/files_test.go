@@ -0,0 +1,187 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"pkg/foo_test.go", true},
+		{"foo.go", false},
+		{"src/main/java/FooTest.java", true},
+		{"src/FooTests.cs", true},
+		{"src/Test.java", false}, // the stem alone is not a suffix
+		{"src/Tests.cs", true},
+		{"src/Latest.java", false},
+		{"src/Contest.java", false},
+		{"app/x.spec.ts", true},
+		{"app/x.test.js", true},
+		{"lib/user_spec.rb", true},
+		{"test_parser.py", true},
+		{"testdata.py", false},
+		{"tests/helpers.py", true},
+		{"pkg/Testing/util.go", true},
+		{"src/__tests__/App.jsx", true},
+		{"attestation/verify.go", false},
+	}
+	for _, tt := range tests {
+		if got := isTestFile(tt.path); got != tt.want {
+			t.Errorf("isTestFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCommunityFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"README.md", FileReadme},
+		{"readme", FileReadme},
+		{"docs/README.rst", FileReadme},
+		{".github/CONTRIBUTING.md", FileContributing},
+		{"docs/CODE_OF_CONDUCT.md", FileCodeOfConduct},
+		{"src/README.md", ""},
+		{"docs/api/README.md", ""},
+		{"LICENSE", FileLicense},
+		{"LICENSE-MIT", FileLicense},
+		{"COPYING.txt", FileLicense},
+		{"licensed.go", ""},
+		{"CHANGELOG.md", FileChangelog},
+		{"CHANGES", FileChangelog},
+		{"docs/changes.rst", FileChangelog},
+		{"CHANGELOG-2023.md", ""},
+		{"docs/history.md", ""},
+		{"NEWS-archive.txt", ""},
+		{"NEWS", ""},
+		{".github/workflows/ci.yml", FileCI},
+		{".github/workflows/release.yaml", FileCI},
+		{".github/workflows/README.md", ""},
+		{".gitlab-ci.yml", FileCI},
+		{".circleci/config.yml", FileCI},
+		{"Jenkinsfile", FileCI},
+		{"ci/Jenkinsfile", ""},
+	}
+	for _, tt := range tests {
+		if got := communityFile(tt.path); got != tt.want {
+			t.Errorf("communityFile(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeFilesCommunity(t *testing.T) {
+	s := AnalyzeFiles([]github.TreeEntry{
+		{Path: "README.md", Type: "blob"},
+		{Path: "docs", Type: "tree"},
+		{Path: "docs/README.md", Type: "blob"},
+		{Path: "docs/history.md", Type: "blob"},
+		{Path: ".github/workflows/ci.yml", Type: "blob"},
+	})
+	// The first match wins
+	if got := s.Community[0]; got.Name != FileReadme || got.Path != "README.md" {
+		t.Errorf("README = %+v", got)
+	}
+	if s.HasCommunityFile(FileChangelog) || s.HasCommunityFile(FileLicense) {
+		t.Error("missing files reported present")
+	}
+	if !s.HasCommunityFile(FileCI) {
+		t.Error("workflow not counted as CI")
+	}
+	if n := len(s.Community); n != len(communityPrefixes)+1 {
+		t.Errorf("got %d community files, want every one listed", n)
+	}
+}
+
+func TestAnalyzeFilesTestsSkipVendored(t *testing.T) {
+	s := AnalyzeFiles([]github.TreeEntry{
+		{Path: "main.go", Type: "blob"},
+		{Path: "util.go", Type: "blob"},
+		{Path: "util_test.go", Type: "blob"},
+		{Path: "vendor", Type: "tree"},
+		{Path: "vendor/lib", Type: "tree"},
+		{Path: "vendor/lib/lib.go", Type: "blob"},
+		{Path: "vendor/lib/lib_test.go", Type: "blob"},
+		{Path: "web", Type: "tree"},
+		{Path: "web/node_modules", Type: "tree"},
+		{Path: "web/node_modules/x/index.js", Type: "blob"},
+		{Path: "web/app.js", Type: "blob"},
+	})
+	want := []LanguageTests{
+		{Language: "Go", Source: 2, Tests: 1, Ratio: 0.5},
+		{Language: "JavaScript", Source: 1},
+	}
+	if !reflect.DeepEqual(s.Tests, want) {
+		t.Errorf("Tests = %+v, want %+v", s.Tests, want)
+	}
+	// Nested vendored directories are reported once, under the outermost
+	if want := []string{"vendor", "web/node_modules"}; !reflect.DeepEqual(s.Vendored, want) {
+		t.Errorf("Vendored = %q, want %q", s.Vendored, want)
+	}
+	if s.Files != 7 || s.Dirs != 4 {
+		t.Errorf("Files, Dirs = %d, %d, want 7, 4", s.Files, s.Dirs)
+	}
+}
+
+func TestAnalyzeFilesSizes(t *testing.T) {
+	s := AnalyzeFiles([]github.TreeEntry{
+		{Path: "a.go", Type: "blob", Size: 10},
+		{Path: "Makefile", Type: "blob", Size: 5},
+		{Path: "cmd/b.go", Type: "blob", Size: 30},
+		{Path: "bin/tool.exe", Type: "blob", Size: 100},
+		{Path: "sub", Type: "commit"},
+	})
+	if s.Bytes != 145 || s.Files != 4 {
+		t.Errorf("Bytes, Files = %d, %d", s.Bytes, s.Files)
+	}
+	wantExts := []ExtensionStats{{".exe", 1, 100}, {".go", 2, 40}, {"(none)", 1, 5}}
+	if !reflect.DeepEqual(s.Extensions, wantExts) {
+		t.Errorf("Extensions = %+v", s.Extensions)
+	}
+	wantDirs := []DirectoryStats{{"bin", 1, 100}, {"cmd", 1, 30}, {".", 2, 15}}
+	if !reflect.DeepEqual(s.Directories, wantDirs) {
+		t.Errorf("Directories = %+v", s.Directories)
+	}
+	if len(s.Binaries) != 1 || s.Largest[0].Path != "bin/tool.exe" {
+		t.Errorf("Binaries = %+v, Largest = %+v", s.Binaries, s.Largest)
+	}
+}
+
+func TestDeepOutliers(t *testing.T) {
+	var tree []github.TreeEntry
+	for i := 0; i < 20; i++ {
+		tree = append(tree, github.TreeEntry{Path: fmt.Sprintf("pkg/f%d.go", i), Type: "blob"})
+	}
+	tree = append(tree,
+		github.TreeEntry{Path: "a/b/c/d/e/f/six.go", Type: "blob"},
+		github.TreeEntry{Path: "a/b/c/d/e/f/g/h/eight.go", Type: "blob"},
+		github.TreeEntry{Path: "a/b/c/d/e/f/g/h/i", Type: "tree"},
+	)
+	s := AnalyzeFiles(tree)
+	if want := []string{"a/b/c/d/e/f/g/h/eight.go"}; !reflect.DeepEqual(s.DeepPaths, want) {
+		t.Errorf("DeepPaths = %q, want %q", s.DeepPaths, want)
+	}
+	if s.MaxDepth != 8 {
+		t.Errorf("MaxDepth = %d, want 8", s.MaxDepth)
+	}
+
+	// Uniformly deep trees have no outliers, however deep
+	var uniform []github.TreeEntry
+	var depths []int
+	for i := 0; i < 5; i++ {
+		uniform = append(uniform, github.TreeEntry{Path: fmt.Sprintf("a/b/c/d/e/f/g/%d.go", i), Type: "blob"})
+		depths = append(depths, 7)
+	}
+	if deep := deepOutliers(uniform, depths); deep != nil {
+		t.Errorf("uniform tree outliers = %q", deep)
+	}
+	if deep := deepOutliers(nil, nil); deep != nil {
+		t.Errorf("empty tree outliers = %q", deep)
+	}
+}
@@ -1,25 +1,365 @@
 package analyzer
 
-import "github.com/agnivo988/Repo-lyzer/internal/github"
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
 
-func CalculateHealth(repo *github.Repo, commits []github.Commit) int {
-	score := 50
+	"gopkg.in/yaml.v3"
 
-	if repo.Description != "" {
-		score += 10
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// HealthInput is the data health rules read. Optional parts are nil when
+// they were not fetched; rules that need them are skipped rather than
+// scored as failing.
+type HealthInput struct {
+	Repo         *github.Repo
+	Commits      *int // commits in the analysis window
+	Contributors *int
+	Files        *FileStats
+	Releases     *ReleaseStats
+	Issues       *IssueHealth
+	Pulls        *PRHealth
+	Security     *SecurityReport
+	TruckFactor  *TruckFactor
+	Now          time.Time // zero means time.Now()
+}
+
+// HealthRule is one rule of a health profile. Weight is the most points it
+// can give; Threshold is the rule's target, nil meaning its default.
+type HealthRule struct {
+	Rule      string   `json:"rule" yaml:"rule"`
+	Weight    float64  `json:"weight" yaml:"weight"`
+	Threshold *float64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+}
+
+// Threshold returns a rule threshold of v, for building profiles in code
+func Threshold(v float64) *float64 {
+	return &v
+}
+
+// HealthProfile is a named set of weighted rules.
+type HealthProfile struct {
+	Name        string       `json:"name" yaml:"name"`
+	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Rules       []HealthRule `json:"rules" yaml:"rules"`
+}
+
+// HealthCheck is one rule's outcome. Skipped checks had no data and are
+// left out of the score.
+type HealthCheck struct {
+	Rule      string
+	Name      string
+	Points    float64
+	MaxPoints float64
+	Skipped   bool
+	Detail    string // why it scored what it did
+}
+
+// HealthReport is the health score with the checks behind it
+type HealthReport struct {
+	Profile string
+	Score   int
+	Checks  []HealthCheck
+}
+
+// Known reports whether any check had data. Without one the score is 0 for
+// lack of evidence rather than a poor result.
+func (r HealthReport) Known() bool {
+	for _, c := range r.Checks {
+		if !c.Skipped {
+			return true
+		}
 	}
-	if repo.Stars > 50 {
-		score += 10
+	return false
+}
+
+// healthRuleDef evaluates one rule, returning the share of its weight
+// earned (0-1) and an explanation, or ok=false when the data is missing
+type healthRuleDef struct {
+	name      string
+	threshold float64
+	eval      func(in HealthInput, threshold float64) (share float64, detail string, ok bool)
+}
+
+// healthRules is the rule catalogue profiles pick from
+var healthRules = map[string]healthRuleDef{
+	"description": {
+		name: "Has a description",
+		eval: func(in HealthInput, _ float64) (float64, string, bool) {
+			if in.Repo.Description == "" {
+				return 0, "no repository description", true
+			}
+			return 1, "repository is described", true
+		},
+	},
+	"stars": {
+		name: "Stars", threshold: 50,
+		eval: func(in HealthInput, t float64) (float64, string, bool) {
+			return atLeast(float64(in.Repo.Stars), t), fmt.Sprintf("%d stars (target %g)", in.Repo.Stars, t), true
+		},
+	},
+	"forks": {
+		name: "Forks", threshold: 10,
+		eval: func(in HealthInput, t float64) (float64, string, bool) {
+			return atLeast(float64(in.Repo.Forks), t), fmt.Sprintf("%d forks (target %g)", in.Repo.Forks, t), true
+		},
+	},
+	"open_issues": {
+		name: "Open issue backlog", threshold: 20,
+		eval: func(in HealthInput, t float64) (float64, string, bool) {
+			return atMost(float64(in.Repo.OpenIssues), t), fmt.Sprintf("%d open issues (at most %g)", in.Repo.OpenIssues, t), true
+		},
+	},
+	"recently_pushed": {
+		name: "Recently pushed", threshold: 90,
+		eval: func(in HealthInput, t float64) (float64, string, bool) {
+			if in.Repo.PushedAt.IsZero() {
+				return 0, "", false
+			}
+			days := math.Floor(in.now().Sub(in.Repo.PushedAt).Hours() / 24)
+			return atMost(days, t), fmt.Sprintf("last push %g days ago (within %g)", days, t), true
+		},
+	},
+	"recent_commits": {
+		name: "Commit activity", threshold: 10,
+		eval: func(in HealthInput, t float64) (float64, string, bool) {
+			if in.Commits == nil {
+				return 0, "", false
+			}
+			return atLeast(float64(*in.Commits), t), fmt.Sprintf("%d commits in the window (target %g)", *in.Commits, t), true
+		},
+	},
+	"contributors": {
+		name: "Contributors", threshold: 2,
+		eval: func(in HealthInput, t float64) (float64, string, bool) {
+			if in.Contributors == nil {
+				return 0, "", false
+			}
+			return atLeast(float64(*in.Contributors), t), fmt.Sprintf("%d contributors (target %g)", *in.Contributors, t), true
+		},
+	},
+	"readme":       communityRule("README", FileReadme),
+	"license":      communityRule("License", FileLicense),
+	"contributing": communityRule("Contributing guide", FileContributing),
+	"ci":           communityRule("CI configuration", FileCI),
+	"tests": {
+		name: "Tests",
+		eval: func(in HealthInput, _ float64) (float64, string, bool) {
+			if in.Files == nil {
+				return 0, "", false
+			}
+			if n := in.Files.TestFiles(); n > 0 {
+				return 1, fmt.Sprintf("%d test files", n), true
+			}
+			return 0, "no test files found", true
+		},
+	},
+	"releases": {
+		name: "Ships releases",
+		eval: func(in HealthInput, _ float64) (float64, string, bool) {
+			if in.Releases == nil {
+				return 0, "", false
+			}
+			if !in.Releases.HasReleases() {
+				return 0, "no releases or tags", true
+			}
+			return 1, fmt.Sprintf("%d releases, %d tags", in.Releases.Releases, in.Releases.Tags), true
+		},
+	},
+	"recent_release": {
+		name: "Recent release", threshold: 180,
+		eval: func(in HealthInput, t float64) (float64, string, bool) {
+			if in.Releases == nil {
+				return 0, "", false
+			}
+			days := in.Releases.DaysSinceLastRelease
+			if days < 0 {
+				return 0, "no releases", true
+			}
+			return atMost(float64(days), t), fmt.Sprintf("%s released %d days ago (within %g)", in.Releases.LastRelease, days, t), true
+		},
+	},
+	"issue_health": {
+		name: "Issue health",
+		eval: func(in HealthInput, _ float64) (float64, string, bool) {
+			if in.Issues == nil || in.Issues.Total == 0 {
+				return 0, "", false
+			}
+			return float64(in.Issues.Score) / 100, fmt.Sprintf("grade %s (%d/100)", in.Issues.Grade, in.Issues.Score), true
+		},
+	},
+	"pr_health": {
+		name: "Pull request health",
+		eval: func(in HealthInput, _ float64) (float64, string, bool) {
+			if in.Pulls == nil || in.Pulls.Total == 0 {
+				return 0, "", false
+			}
+			return float64(in.Pulls.Score) / 100, fmt.Sprintf("grade %s (%d/100)", in.Pulls.Grade, in.Pulls.Score), true
+		},
+	},
+	"security": {
+		name: "Security posture",
+		eval: func(in HealthInput, _ float64) (float64, string, bool) {
+			if in.Security == nil {
+				return 0, "", false
+			}
+			checked := 0
+			for _, c := range in.Security.Checks {
+				if !c.Skipped {
+					checked++
+				}
+			}
+			if checked == 0 {
+				return 0, "", false
+			}
+			return float64(in.Security.Score) / 100, fmt.Sprintf("grade %s (%d/100)", in.Security.Grade, in.Security.Score), true
+		},
+	},
+	"bus_factor": {
+		name: "Bus factor", threshold: 2,
+		eval: func(in HealthInput, t float64) (float64, string, bool) {
+			if in.TruckFactor == nil || in.TruckFactor.Value == 0 {
+				return 0, "", false
+			}
+			return atLeast(float64(in.TruckFactor.Value), t), fmt.Sprintf("%d (target %g)", in.TruckFactor.Value, t), true
+		},
+	},
+}
+
+// communityRule checks for one of the standard project files
+func communityRule(name, file string) healthRuleDef {
+	return healthRuleDef{
+		name: name,
+		eval: func(in HealthInput, _ float64) (float64, string, bool) {
+			if in.Files == nil {
+				return 0, "", false
+			}
+			for _, c := range in.Files.Community {
+				if c.Name == file && c.Present() {
+					return 1, c.Path, true
+				}
+			}
+			return 0, "not found", true
+		},
 	}
-	if len(commits) > 10 {
-		score += 20
+}
+
+// HealthRuleIDs lists the rules a profile can use, sorted
+func HealthRuleIDs() []string {
+	ids := make([]string, 0, len(healthRules))
+	for id := range healthRules {
+		ids = append(ids, id)
 	}
-	if repo.OpenIssues < 20 {
-		score += 10
+	sort.Strings(ids)
+	return ids
+}
+
+// Validate checks that the profile only uses known rules with sensible
+// weights and thresholds
+func (p HealthProfile) Validate() error {
+	prefix := "health profile"
+	if p.Name != "" {
+		prefix = fmt.Sprintf("health profile %q", p.Name)
+	}
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("%s has no rules", prefix)
+	}
+	total := 0.0
+	seen := map[string]bool{}
+	for _, r := range p.Rules {
+		if _, ok := healthRules[r.Rule]; !ok {
+			return fmt.Errorf("%s: unknown rule %q (available: %s)", prefix, r.Rule, strings.Join(HealthRuleIDs(), ", "))
+		}
+		if seen[r.Rule] {
+			return fmt.Errorf("%s: rule %q is listed twice", prefix, r.Rule)
+		}
+		seen[r.Rule] = true
+		if r.Weight < 0 || (r.Threshold != nil && *r.Threshold < 0) {
+			return fmt.Errorf("%s: rule %q has a negative weight or threshold", prefix, r.Rule)
+		}
+		total += r.Weight
 	}
+	if total == 0 {
+		return fmt.Errorf("%s: every rule weighs zero", prefix)
+	}
+	return nil
+}
 
-	if score > 100 {
-		score = 100
+// ParseHealthProfile reads a profile written in YAML or JSON
+func ParseHealthProfile(data []byte) (HealthProfile, error) {
+	var p HealthProfile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		return p, fmt.Errorf("invalid health profile: %w", err)
+	}
+	for i := range p.Rules {
+		p.Rules[i].Rule = strings.ToLower(strings.TrimSpace(p.Rules[i].Rule))
+	}
+	return p, p.Validate()
+}
+
+// EvaluateHealth scores in against the profile's rules. The score is the
+// points earned as a share of the points available from rules that had
+// data, so missing data neither helps nor hurts.
+func EvaluateHealth(profile HealthProfile, in HealthInput) HealthReport {
+	report := HealthReport{Profile: profile.Name}
+	var earned, available float64
+	for _, r := range profile.Rules {
+		def, ok := healthRules[r.Rule]
+		if !ok {
+			continue
+		}
+		threshold := def.threshold
+		if r.Threshold != nil {
+			threshold = *r.Threshold
+		}
+
+		check := HealthCheck{Rule: r.Rule, Name: def.name, MaxPoints: r.Weight}
+		share, detail, ok := def.eval(in, threshold)
+		if !ok {
+			check.Skipped, check.Detail = true, "not checked"
+		} else {
+			check.Points = math.Round(r.Weight*min(max(share, 0), 1)*10) / 10
+			check.Detail = detail
+			earned += check.Points
+			available += check.MaxPoints
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	if available > 0 {
+		report.Score = int(earned/available*100 + 0.5)
+	}
+	return report
+}
+
+func (in HealthInput) now() time.Time {
+	if in.Now.IsZero() {
+		return time.Now()
+	}
+	return in.Now
+}
+
+// atLeast is the share of target reached, capped at 1
+func atLeast(value, target float64) float64 {
+	if target <= 0 || value >= target {
+		return 1
+	}
+	return value / target
+}
+
+// atMost is 1 within the limit, falling off as value exceeds it
+func atMost(value, limit float64) float64 {
+	if value <= limit {
+		return 1
+	}
+	if value <= 0 {
+		return 0
 	}
-	return score
+	return limit / value
 }
@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultHealthProfile is the profile used unless another is chosen
+const DefaultHealthProfile = "default"
+
+// healthPresets are the built-in profiles, by name
+var healthPresets = map[string]HealthProfile{
+	"default": {
+		Name:        "default",
+		Description: "balanced upkeep, documentation and activity",
+		Rules: []HealthRule{
+			{Rule: "description", Weight: 10},
+			{Rule: "readme", Weight: 5},
+			{Rule: "license", Weight: 5},
+			{Rule: "contributing", Weight: 5},
+			{Rule: "ci", Weight: 5},
+			{Rule: "tests", Weight: 5},
+			{Rule: "stars", Weight: 10, Threshold: Threshold(50)},
+			{Rule: "recent_commits", Weight: 20, Threshold: Threshold(10)},
+			{Rule: "open_issues", Weight: 10, Threshold: Threshold(20)},
+			{Rule: "recently_pushed", Weight: 5, Threshold: Threshold(90)},
+			{Rule: "issue_health", Weight: 10},
+			{Rule: "pr_health", Weight: 10},
+		},
+	},
+	"maintainer": {
+		Name:        "maintainer",
+		Description: "is the project kept up: steady commits, triage, review and spread ownership",
+		Rules: []HealthRule{
+			{Rule: "recent_commits", Weight: 20, Threshold: Threshold(50)},
+			{Rule: "issue_health", Weight: 20},
+			{Rule: "pr_health", Weight: 20},
+			{Rule: "contributors", Weight: 10, Threshold: Threshold(5)},
+			{Rule: "bus_factor", Weight: 10, Threshold: Threshold(3)},
+			{Rule: "ci", Weight: 10},
+			{Rule: "tests", Weight: 10},
+		},
+	},
+	"recruiter": {
+		Name:        "recruiter",
+		Description: "does the project show good engineering habits at a glance",
+		Rules: []HealthRule{
+			{Rule: "description", Weight: 10},
+			{Rule: "readme", Weight: 15},
+			{Rule: "license", Weight: 10},
+			{Rule: "tests", Weight: 15},
+			{Rule: "ci", Weight: 10},
+			{Rule: "recent_commits", Weight: 20, Threshold: Threshold(20)},
+			{Rule: "recently_pushed", Weight: 10, Threshold: Threshold(30)},
+			{Rule: "stars", Weight: 10, Threshold: Threshold(25)},
+		},
+	},
+	"security": {
+		Name:        "security",
+		Description: "security posture and the upkeep that keeps it current",
+		Rules: []HealthRule{
+			{Rule: "security", Weight: 50},
+			{Rule: "license", Weight: 10},
+			{Rule: "ci", Weight: 10},
+			{Rule: "tests", Weight: 10},
+			{Rule: "bus_factor", Weight: 10, Threshold: Threshold(2)},
+			{Rule: "recent_release", Weight: 10, Threshold: Threshold(180)},
+		},
+	},
+	"adoption": {
+		Name:        "adoption",
+		Description: "is the project safe to depend on: traction, releases and responsiveness",
+		Rules: []HealthRule{
+			{Rule: "stars", Weight: 20, Threshold: Threshold(1000)},
+			{Rule: "forks", Weight: 10, Threshold: Threshold(100)},
+			{Rule: "contributors", Weight: 15, Threshold: Threshold(10)},
+			{Rule: "releases", Weight: 10},
+			{Rule: "recent_release", Weight: 15, Threshold: Threshold(180)},
+			{Rule: "license", Weight: 10},
+			{Rule: "readme", Weight: 10},
+			{Rule: "issue_health", Weight: 10},
+		},
+	},
+}
+
+// HealthPreset returns a copy of the named built-in profile
+func HealthPreset(name string) (HealthProfile, bool) {
+	p, ok := healthPresets[strings.ToLower(name)]
+	if !ok {
+		return HealthProfile{}, false
+	}
+	p.Rules = append([]HealthRule(nil), p.Rules...)
+	for i, r := range p.Rules {
+		if r.Threshold != nil {
+			p.Rules[i].Threshold = Threshold(*r.Threshold)
+		}
+	}
+	return p, true
+}
+
+// HealthPresetNames lists the built-in profiles, default first
+func HealthPresetNames() []string {
+	names := []string{DefaultHealthProfile}
+	for name := range healthPresets {
+		if name != DefaultHealthProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
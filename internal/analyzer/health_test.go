@@ -0,0 +1,207 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestParseHealthProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"yaml", "name: mine\nrules:\n  - rule: readme\n    weight: 10\n  - rule: Stars\n    weight: 5\n    threshold: 1000\n", ""},
+		{"json", `{"name": "mine", "rules": [{"rule": "license", "weight": 1}]}`, ""},
+		{"unnamed", "rules:\n  - rule: ci\n    weight: 1\n", ""},
+		{"no rules", "name: empty\n", `health profile "empty" has no rules`},
+		{"unknown rule", "rules:\n  - rule: vibes\n    weight: 1\n", `unknown rule "vibes"`},
+		{"duplicate rule", "rules:\n  - rule: ci\n    weight: 1\n  - rule: CI\n    weight: 2\n", `rule "ci" is listed twice`},
+		{"negative weight", "rules:\n  - rule: ci\n    weight: -1\n", "negative weight or threshold"},
+		{"negative threshold", "rules:\n  - rule: stars\n    weight: 1\n    threshold: -5\n", "negative weight or threshold"},
+		{"zero total", "rules:\n  - rule: ci\n    weight: 0\n  - rule: tests\n    weight: 0\n", "every rule weighs zero"},
+		{"unknown field", "rules:\n  - rule: ci\n    weigth: 1\n", "invalid health profile"},
+		{"not a profile", "[1, 2]", "invalid health profile"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseHealthProfile([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range p.Rules {
+				if r.Rule != strings.ToLower(r.Rule) {
+					t.Errorf("rule %q was not normalized", r.Rule)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthPresetsAreValid(t *testing.T) {
+	for _, name := range HealthPresetNames() {
+		p, ok := HealthPreset(name)
+		if !ok {
+			t.Errorf("preset %q listed but not found", name)
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			t.Errorf("preset %q: %v", name, err)
+		}
+	}
+	if names := HealthPresetNames(); names[0] != DefaultHealthProfile {
+		t.Errorf("presets start with %q, want the default", names[0])
+	}
+	// Presets hand out copies
+	p, _ := HealthPreset(DefaultHealthProfile)
+	p.Rules[0].Weight = 1000
+	if again, _ := HealthPreset(DefaultHealthProfile); again.Rules[0].Weight == 1000 {
+		t.Error("changing a preset's rules changed the preset")
+	}
+	for i, r := range p.Rules {
+		if r.Threshold != nil {
+			*p.Rules[i].Threshold = -1
+		}
+	}
+	again, _ := HealthPreset(DefaultHealthProfile)
+	if err := again.Validate(); err != nil {
+		t.Errorf("changing a preset's thresholds changed the preset: %v", err)
+	}
+}
+
+func TestEvaluateHealth(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := &github.Repo{Description: "a tool", Stars: 25, PushedAt: now.AddDate(0, 0, -10)}
+	commits := 5
+
+	profile := HealthProfile{Name: "test", Rules: []HealthRule{
+		{Rule: "description", Weight: 10},
+		{Rule: "stars", Weight: 20},                                    // 25 of 50 stars: 10 points
+		{Rule: "recent_commits", Weight: 10, Threshold: Threshold(20)}, // 5 of 20: 2.5 points
+		{Rule: "tests", Weight: 30},                                    // no file data: skipped
+		{Rule: "recently_pushed", Weight: 10},                          // within 90 days
+	}}
+	report := EvaluateHealth(profile, HealthInput{Repo: repo, Commits: &commits, Now: now})
+
+	if report.Profile != "test" || len(report.Checks) != 5 {
+		t.Fatalf("report = %+v", report)
+	}
+	wantPoints := []float64{10, 10, 2.5, 0, 10}
+	for i, c := range report.Checks {
+		if c.Points != wantPoints[i] {
+			t.Errorf("%s scored %v, want %v", c.Rule, c.Points, wantPoints[i])
+		}
+		if c.MaxPoints != profile.Rules[i].Weight {
+			t.Errorf("%s max points = %v", c.Rule, c.MaxPoints)
+		}
+	}
+	if !report.Checks[3].Skipped || report.Checks[3].Detail != "not checked" {
+		t.Errorf("tests check = %+v, want skipped", report.Checks[3])
+	}
+	// 32.5 of the 50 points that had data; the skipped 30 are left out
+	if report.Score != 65 {
+		t.Errorf("Score = %d, want 65", report.Score)
+	}
+	if !report.Known() {
+		t.Error("report with checks run is not known")
+	}
+}
+
+func TestEvaluateHealthSkipped(t *testing.T) {
+	profile := HealthProfile{Rules: []HealthRule{
+		{Rule: "tests", Weight: 50},
+		{Rule: "recently_pushed", Weight: 50},
+	}}
+	// A zero push date is missing data, not a push long ago
+	report := EvaluateHealth(profile, HealthInput{Repo: &github.Repo{}})
+	if report.Score != 0 || report.Known() {
+		t.Errorf("report = %+v, want unknown with score 0", report)
+	}
+	for _, c := range report.Checks {
+		if !c.Skipped {
+			t.Errorf("%s was not skipped", c.Rule)
+		}
+	}
+}
+
+func TestEvaluateHealthRescalesToAvailable(t *testing.T) {
+	files := &FileStats{}
+	in := HealthInput{Repo: &github.Repo{Description: "x"}, Files: files}
+	small := EvaluateHealth(HealthProfile{Rules: []HealthRule{{Rule: "description", Weight: 1}}}, in)
+	large := EvaluateHealth(HealthProfile{Rules: []HealthRule{
+		{Rule: "description", Weight: 1},
+		{Rule: "contributors", Weight: 100}, // not fetched
+	}}, in)
+	if small.Score != 100 || large.Score != 100 {
+		t.Errorf("scores = %d, %d, want 100 whatever the skipped weight", small.Score, large.Score)
+	}
+
+	half := EvaluateHealth(HealthProfile{Rules: []HealthRule{
+		{Rule: "description", Weight: 3},
+		{Rule: "tests", Weight: 1}, // no test files
+	}}, in)
+	if half.Score != 75 {
+		t.Errorf("Score = %d, want 75", half.Score)
+	}
+}
+
+func TestEvaluateHealthThresholds(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := &github.Repo{OpenIssues: 40, PushedAt: now.AddDate(0, 0, -1000)}
+	report := EvaluateHealth(HealthProfile{Rules: []HealthRule{
+		{Rule: "open_issues", Weight: 10}, // twice the default 20
+	}}, HealthInput{Repo: repo, Now: now})
+	if p := report.Checks[0].Points; p >= 10 || p <= 0 {
+		t.Errorf("40 open issues against 20 scored %v", p)
+	}
+
+	report = EvaluateHealth(HealthProfile{Rules: []HealthRule{
+		{Rule: "open_issues", Weight: 10, Threshold: Threshold(50)},
+		{Rule: "recently_pushed", Weight: 10, Threshold: Threshold(2000)},
+	}}, HealthInput{Repo: repo, Now: now})
+	for _, c := range report.Checks {
+		if c.Points != 10 {
+			t.Errorf("%s with a raised threshold scored %v, want 10", c.Rule, c.Points)
+		}
+	}
+}
+
+func TestEvaluateHealthZeroThreshold(t *testing.T) {
+	// An explicit zero is a target of its own, not the default
+	p, err := ParseHealthProfile([]byte("name: strict\nrules:\n  - rule: open_issues\n    weight: 10\n    threshold: 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rules[0].Threshold == nil || *p.Rules[0].Threshold != 0 {
+		t.Fatalf("threshold = %v, want an explicit 0", p.Rules[0].Threshold)
+	}
+	lenient := HealthProfile{Rules: []HealthRule{{Rule: "open_issues", Weight: 10}}}
+
+	tests := []struct {
+		name    string
+		profile HealthProfile
+		open    int
+		want    float64
+	}{
+		{"none open", p, 0, 10},
+		{"some open", p, 3, 0},
+		{"default threshold", lenient, 3, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := EvaluateHealth(tt.profile, HealthInput{Repo: &github.Repo{OpenIssues: tt.open}})
+			if got := report.Checks[0].Points; got != tt.want {
+				t.Errorf("%d open issues scored %v, want %v (%s)", tt.open, got, tt.want, report.Checks[0].Detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// StaleAfter is how long an open issue can go without updates before it
+// counts as stale
+const StaleAfter = 90 * 24 * time.Hour
+
+// LabelCount is how many analyzed issues carry a label
+type LabelCount struct {
+	Name  string
+	Count int
+}
+
+// IssueHealth summarises how a repository's issue tracker is looked after
+type IssueHealth struct {
+	Total               int
+	Open                int
+	Closed              int
+	OpenClosedRatio     float64 // open per closed issue; Open when none are closed
+	MedianTimeToClose   time.Duration
+	MedianFirstResponse time.Duration
+	ResponseKnown       int  // issues whose comments were all fetched, or that have none
+	Responded           int  // of ResponseKnown, issues that got a comment from someone other than the author
+	ResponsePartial     bool // the comment listing stopped short, so some issues have no response data
+	Stale               int  // open issues not updated within StaleAfter
+	Labels              []LabelCount
+	Score               int
+	Grade               string
+	Verdict             string
+}
+
+// Summary is the one-line verdict used in recruiter output
+func (h IssueHealth) Summary() string {
+	if h.Total == 0 {
+		return "No issues"
+	}
+	return fmt.Sprintf("%s (%s)", h.Grade, h.Verdict)
+}
+
+// AnalyzeIssues grades an issue tracker. comments is a repository-wide
+// listing, newest first; complete says it reaches back past the oldest
+// issue. When it does not, only issues created after the oldest comment
+// fetched (or without comments at all) count towards the response stats,
+// so issues with no comment data are not scored as unanswered. The
+// earliest non-author comment of each issue is its first response.
+func AnalyzeIssues(issues []github.Issue, comments []github.IssueComment, complete bool) IssueHealth {
+	h := IssueHealth{Grade: "-", Verdict: "No issues"}
+	if len(issues) == 0 {
+		return h
+	}
+
+	byNumber := make(map[int]github.Issue, len(issues))
+	labels := map[string]int{}
+	var closeTimes []time.Duration
+	now := time.Now()
+
+	for _, issue := range issues {
+		byNumber[issue.Number] = issue
+		for _, l := range issue.Labels {
+			labels[l.Name]++
+		}
+
+		if issue.State == "closed" {
+			h.Closed++
+			if issue.ClosedAt != nil {
+				closeTimes = append(closeTimes, issue.ClosedAt.Sub(issue.CreatedAt))
+			}
+			continue
+		}
+		h.Open++
+		if now.Sub(issue.UpdatedAt) > StaleAfter {
+			h.Stale++
+		}
+	}
+	h.Total = len(issues)
+
+	created := make([]time.Time, len(comments))
+	firstResponse := map[int]time.Duration{}
+	for i, c := range comments {
+		created[i] = c.CreatedAt
+		issue, ok := byNumber[c.IssueNumber()]
+		if !ok || c.User.Login == issue.User.Login || c.User.Type == "Bot" {
+			continue
+		}
+		wait := c.CreatedAt.Sub(issue.CreatedAt)
+		if prev, seen := firstResponse[issue.Number]; !seen || wait < prev {
+			firstResponse[issue.Number] = wait
+		}
+	}
+	covered := commentCoverage(created, complete)
+	var responseTimes []time.Duration
+	for _, issue := range issues {
+		if issue.Comments > 0 && !covered(issue.CreatedAt) {
+			h.ResponsePartial = true
+			continue
+		}
+		h.ResponseKnown++
+		if wait, ok := firstResponse[issue.Number]; ok {
+			responseTimes = append(responseTimes, wait)
+		}
+	}
+	h.Responded = len(responseTimes)
+
+	if h.Closed > 0 {
+		h.OpenClosedRatio = float64(h.Open) / float64(h.Closed)
+	} else {
+		h.OpenClosedRatio = float64(h.Open)
+	}
+	h.MedianTimeToClose = medianDuration(closeTimes)
+	h.MedianFirstResponse = medianDuration(responseTimes)
+
+	for name, n := range labels {
+		h.Labels = append(h.Labels, LabelCount{Name: name, Count: n})
+	}
+	sort.Slice(h.Labels, func(i, j int) bool {
+		if h.Labels[i].Count != h.Labels[j].Count {
+			return h.Labels[i].Count > h.Labels[j].Count
+		}
+		return h.Labels[i].Name < h.Labels[j].Name
+	})
+
+	h.Score = issueScore(h)
+	h.Grade, h.Verdict = healthGrade(h.Score)
+	return h
+}
+
+// issueScore weighs close rate (40), time to close (20), time to first
+// response (20) and the share of open issues that are stale (20). Without
+// response data for any issue the other parts are scaled up to 100.
+func issueScore(h IssueHealth) int {
+	score := 40 * float64(h.Closed) / float64(h.Total)
+	available := 100.0
+
+	day := 24 * time.Hour
+	switch {
+	case h.Closed == 0:
+	case h.MedianTimeToClose <= 7*day:
+		score += 20
+	case h.MedianTimeToClose <= 30*day:
+		score += 14
+	case h.MedianTimeToClose <= 90*day:
+		score += 8
+	default:
+		score += 3
+	}
+
+	switch {
+	case h.ResponseKnown == 0:
+		available -= 20
+	case h.Responded == 0:
+	case h.MedianFirstResponse <= 2*day:
+		score += 20
+	case h.MedianFirstResponse <= 7*day:
+		score += 14
+	case h.MedianFirstResponse <= 30*day:
+		score += 8
+	default:
+		score += 3
+	}
+
+	if h.Open == 0 {
+		score += 20
+	} else {
+		score += 20 * (1 - float64(h.Stale)/float64(h.Open))
+	}
+	return int(score*100/available + 0.5)
+}
+
+// commentCoverage reports, for an item created at some time, whether
+// every comment on it is in a newest-first listing that holds comments
+// created at times. A complete listing covers everything; a cut-short one
+// only what was created after its oldest comment.
+func commentCoverage(times []time.Time, complete bool) func(created time.Time) bool {
+	if complete {
+		return func(time.Time) bool { return true }
+	}
+	if len(times) == 0 {
+		return func(time.Time) bool { return false }
+	}
+	oldest := times[0]
+	for _, t := range times {
+		if t.Before(oldest) {
+			oldest = t
+		}
+	}
+	return func(created time.Time) bool { return created.After(oldest) }
+}
+
+// healthGrade turns a 0-100 score into a letter grade and verdict
+func healthGrade(score int) (string, string) {
+	switch {
+	case score >= 85:
+		return "A", "Excellent"
+	case score >= 70:
+		return "B", "Good"
+	case score >= 55:
+		return "C", "Fair"
+	case score >= 40:
+		return "D", "Needs Attention"
+	default:
+		return "F", "Neglected"
+	}
+}
+
+func medianDuration(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func testIssue(number int, created time.Time, comments int) github.Issue {
+	closed := created.Add(24 * time.Hour)
+	return github.Issue{
+		Number:    number,
+		State:     "closed",
+		User:      github.User{Login: "author"},
+		Comments:  comments,
+		CreatedAt: created,
+		UpdatedAt: closed,
+		ClosedAt:  &closed,
+	}
+}
+
+func testComment(issue int, created time.Time) github.IssueComment {
+	return github.IssueComment{
+		IssueURL:  fmt.Sprintf("https://api.github.com/repos/o/r/issues/%d", issue),
+		User:      github.User{Login: "maintainer", Type: "User"},
+		CreatedAt: created,
+	}
+}
+
+func TestAnalyzeIssuesResponseCoverage(t *testing.T) {
+	base := time.Now().Add(-30 * 24 * time.Hour)
+	day := 24 * time.Hour
+	issues := []github.Issue{
+		testIssue(3, base.Add(20*day), 1),
+		testIssue(2, base.Add(10*day), 1),
+		testIssue(1, base, 2),
+		testIssue(4, base.Add(-day), 0), // never commented on
+	}
+	// Newest first, as the API lists them
+	all := []github.IssueComment{
+		testComment(3, base.Add(20*day+time.Hour)),
+		testComment(2, base.Add(10*day+2*time.Hour)),
+		testComment(1, base.Add(3*day)),
+		testComment(1, base.Add(time.Hour)),
+	}
+
+	tests := []struct {
+		name          string
+		comments      []github.IssueComment
+		complete      bool
+		wantKnown     int
+		wantResponded int
+		wantPartial   bool
+		wantMedian    time.Duration
+	}{
+		{"complete listing", all, true, 4, 3, false, time.Hour},
+		// Issue 2 predates the oldest comment fetched, so an earlier reply
+		// may be missing
+		{"capped listing drops older commented issues", all[:2], false, 2, 1, true, time.Hour},
+		{"no comment data", nil, false, 1, 0, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := AnalyzeIssues(issues, tt.comments, tt.complete)
+			if h.ResponseKnown != tt.wantKnown || h.Responded != tt.wantResponded || h.ResponsePartial != tt.wantPartial {
+				t.Errorf("known, responded, partial = %d, %d, %v, want %d, %d, %v",
+					h.ResponseKnown, h.Responded, h.ResponsePartial, tt.wantKnown, tt.wantResponded, tt.wantPartial)
+			}
+			if h.MedianFirstResponse != tt.wantMedian {
+				t.Errorf("MedianFirstResponse = %v, want %v", h.MedianFirstResponse, tt.wantMedian)
+			}
+		})
+	}
+}
+
+func TestIssueScoreWithoutResponseData(t *testing.T) {
+	h := IssueHealth{Total: 4, Closed: 4, MedianTimeToClose: 24 * time.Hour}
+
+	// Close rate, time to close and no stale issues give 80 of 80 points
+	if got := issueScore(h); got != 100 {
+		t.Errorf("score without response data = %d, want 100", got)
+	}
+
+	h.ResponseKnown = 4
+	if got := issueScore(h); got != 80 {
+		t.Errorf("score with every issue unanswered = %d, want 80", got)
+	}
+
+	h.Responded, h.MedianFirstResponse = 4, time.Hour
+	if got := issueScore(h); got != 100 {
+		t.Errorf("score with quick answers = %d, want 100", got)
+	}
+}
@@ -1,49 +1,275 @@
 package analyzer
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/agnivo988/Repo-lyzer/internal/github"
 )
 
-func RepoMaturityScore(repo *github.Repo, commits int, contributors int, hasReleases bool) (int, string) {
-	score := 0
+// MaturityInput is the data maturity signals read. Optional parts are nil
+// when they were not fetched; their signals are left out and lower the
+// confidence instead of the score.
+type MaturityInput struct {
+	Repo     *github.Repo
+	Commits  []github.Commit // commits in the analysis window, nil if not fetched
+	Releases *ReleaseStats
+	Issues   *IssueHealth
+	Files    *FileStats
+	Now      time.Time // zero means time.Now()
+}
 
-	// Age
-	ageYears := time.Since(repo.CreatedAt).Hours() / (24 * 365)
-	if ageYears >= 1 {
-		score += 20
-	}
+// MaturitySignal is one normalized sub-score. Unknown signals had no data
+// and are left out of the score.
+type MaturitySignal struct {
+	Signal string
+	Name   string
+	Score  int // 0-100
+	Weight float64
+	Known  bool
+	Detail string
+}
 
-	// Activity
-	if commits > 100 {
-		score += 25
-	}
+// Maturity is the maturity score with the signals behind it. Confidence is
+// the share of the signals' weight that had data, 0-1.
+type Maturity struct {
+	Score      int
+	Level      string
+	Confidence float64
+	Signals    []MaturitySignal
+}
 
-	// Contributors
-	if contributors > 1 {
-		score += 20
+// ConfidenceLabel names the confidence band: high, medium or low
+func (m Maturity) ConfidenceLabel() string {
+	switch {
+	case m.Confidence >= 0.8:
+		return "high"
+	case m.Confidence >= 0.5:
+		return "medium"
 	}
+	return "low"
+}
 
-	// Releases
-	if hasReleases {
-		score += 20
-	}
+// Maturity signal tuning. Ratios rather than counts keep the score
+// comparable between a 10-star library and a 50k-star framework.
+const (
+	matureAgeYears = 3
+	// Open issues per star at or below which the backlog scores full
+	// marks, and at or above which it scores nothing. Stars are padded
+	// so a handful of issues on a new repository is not a crisis.
+	issueLoadGood  = 0.02
+	issueLoadBad   = 0.2
+	issueLoadStars = 50
+	// Share of issues closed that scores full marks
+	issueCloseTarget = 0.8
+	// Versions shipped that score full marks for release history
+	releaseHistoryTarget = 5
+	// Commits and days of history needed to judge contributor growth
+	growthMinCommits = 10
+	growthMinDays    = 30
+)
 
-	// Issues sanity
-	if repo.OpenIssues < 50 {
-		score += 15
+// maturitySignals are the signals in display order with their weights,
+// which add up to 100
+var maturitySignals = []struct {
+	signal string
+	name   string
+	weight float64
+	eval   func(in MaturityInput) (score float64, detail string, ok bool)
+}{
+	{"age", "Age", 15, maturityAge},
+	{"issue_load", "Open issues per star", 15, maturityIssueLoad},
+	{"issue_close_rate", "Issue close rate", 15, maturityIssueCloseRate},
+	{"releases", "Release history", 15, maturityReleases},
+	{"contributor_growth", "Contributor growth", 10, maturityContributorGrowth},
+	{"documentation", "Documentation", 15, maturityDocumentation},
+	{"ci", "CI and tests", 15, maturityCI},
+}
+
+// AssessMaturity scores a repository's maturity as the weighted mean of
+// the signals that had data
+func AssessMaturity(in MaturityInput) Maturity {
+	var m Maturity
+	var earned, known, total float64
+	for _, s := range maturitySignals {
+		total += s.weight
+		signal := MaturitySignal{Signal: s.signal, Name: s.name, Weight: s.weight}
+		score, detail, ok := s.eval(in)
+		if !ok {
+			signal.Detail = "not checked"
+			m.Signals = append(m.Signals, signal)
+			continue
+		}
+		score = min(max(score, 0), 100)
+		signal.Known = true
+		signal.Score = int(math.Round(score))
+		signal.Detail = detail
+		earned += score * s.weight
+		known += s.weight
+		m.Signals = append(m.Signals, signal)
+	}
+	if known == 0 {
+		m.Level = "Unknown"
+		return m
 	}
+	m.Score = int(math.Round(earned / known))
+	m.Confidence = math.Round(known/total*100) / 100
+	m.Level = maturityLevel(m.Score)
+	return m
+}
 
-	level := "Prototype"
+func maturityLevel(score int) string {
 	switch {
 	case score >= 80:
-		level = "Production-Ready"
+		return "Production-Ready"
 	case score >= 60:
-		level = "Stable"
+		return "Stable"
 	case score >= 40:
-		level = "Growing"
+		return "Growing"
+	}
+	return "Prototype"
+}
+
+func maturityAge(in MaturityInput) (float64, string, bool) {
+	if in.Repo == nil || in.Repo.CreatedAt.IsZero() {
+		return 0, "", false
+	}
+	years := in.now().Sub(in.Repo.CreatedAt).Hours() / (24 * 365)
+	return min(years/matureAgeYears, 1) * 100, fmt.Sprintf("%.1f years old", years), true
+}
+
+func maturityIssueLoad(in MaturityInput) (float64, string, bool) {
+	if in.Repo == nil {
+		return 0, "", false
+	}
+	// GitHub counts open pull requests as open issues too
+	load := float64(in.Repo.OpenIssues) / float64(in.Repo.Stars+issueLoadStars)
+	score := (issueLoadBad - load) / (issueLoadBad - issueLoadGood) * 100
+	return score, fmt.Sprintf("%d open for %d stars", in.Repo.OpenIssues, in.Repo.Stars), true
+}
+
+func maturityIssueCloseRate(in MaturityInput) (float64, string, bool) {
+	if in.Issues == nil || in.Issues.Total == 0 {
+		return 0, "", false
+	}
+	rate := float64(in.Issues.Closed) / float64(in.Issues.Total)
+	return min(rate/issueCloseTarget, 1) * 100, fmt.Sprintf("%.0f%% of %d issues closed", rate*100, in.Issues.Total), true
+}
+
+func maturityReleases(in MaturityInput) (float64, string, bool) {
+	if in.Releases == nil {
+		return 0, "", false
+	}
+	s := in.Releases
+	if !s.HasReleases() {
+		return 0, "no releases or tags", true
+	}
+	versions := max(s.Releases, s.Tags)
+	score := 50 + 25*min(float64(versions)/releaseHistoryTarget, 1)
+	detail := fmt.Sprintf("%d releases, %d tags", s.Releases, s.Tags)
+	if s.DaysSinceLastRelease >= 0 {
+		if s.DaysSinceLastRelease <= 365 {
+			score += 25
+		}
+		detail += fmt.Sprintf(", last %d days ago", s.DaysSinceLastRelease)
+	}
+	return score, detail, true
+}
+
+// maturityContributorGrowth compares the authors of the later half of the
+// commit history with those of the earlier half: a project keeping or
+// growing its contributors scores full marks, whatever its size.
+func maturityContributorGrowth(in MaturityInput) (float64, string, bool) {
+	if len(in.Commits) < growthMinCommits {
+		return 0, "", false
+	}
+	var oldest, newest time.Time
+	for _, c := range in.Commits {
+		date := c.Commit.Author.Date
+		if oldest.IsZero() || date.Before(oldest) {
+			oldest = date
+		}
+		if date.After(newest) {
+			newest = date
+		}
+	}
+	if newest.Sub(oldest) < growthMinDays*24*time.Hour {
+		return 0, "", false
+	}
+
+	mid := oldest.Add(newest.Sub(oldest) / 2)
+	earlier, later := map[string]bool{}, map[string]bool{}
+	for _, c := range in.Commits {
+		if c.Commit.Author.Date.Before(mid) {
+			earlier[c.AuthorLogin()] = true
+		} else {
+			later[c.AuthorLogin()] = true
+		}
+	}
+	joined := 0
+	for author := range later {
+		if !earlier[author] {
+			joined++
+		}
+	}
+	score := min(float64(len(later))/float64(len(earlier)), 1) * 100
+	return score, fmt.Sprintf("%d authors recently vs %d before, %d new", len(later), len(earlier), joined), true
+}
+
+func maturityDocumentation(in MaturityInput) (float64, string, bool) {
+	if in.Files == nil {
+		return 0, "", false
+	}
+	docs := []struct {
+		name   string
+		points float64
+	}{
+		{FileReadme, 40},
+		{FileLicense, 30},
+		{FileContributing, 15},
+		{FileChangelog, 15},
+	}
+	score := 0.0
+	var missing []string
+	for _, d := range docs {
+		if in.Files.HasCommunityFile(d.name) {
+			score += d.points
+		} else {
+			missing = append(missing, d.name)
+		}
+	}
+	if len(missing) == 0 {
+		return score, "README, license, contributing guide and changelog", true
+	}
+	return score, "missing " + strings.Join(missing, ", "), true
+}
+
+func maturityCI(in MaturityInput) (float64, string, bool) {
+	if in.Files == nil {
+		return 0, "", false
+	}
+	score := 0.0
+	var found []string
+	if in.Files.HasCommunityFile(FileCI) {
+		score += 70
+		found = append(found, "CI configured")
+	} else {
+		found = append(found, "no CI")
 	}
+	if n := in.Files.TestFiles(); n > 0 {
+		score += 30
+		found = append(found, fmt.Sprintf("%d test files", n))
+	} else {
+		found = append(found, "no tests")
+	}
+	return score, strings.Join(found, ", "), true
+}
 
-	return score, level
+func (in MaturityInput) now() time.Time {
+	if in.Now.IsZero() {
+		return time.Now()
+	}
+	return in.Now
 }
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+var maturityNow = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func signal(t *testing.T, m Maturity, id string) MaturitySignal {
+	t.Helper()
+	for _, s := range m.Signals {
+		if s.Signal == id {
+			return s
+		}
+	}
+	t.Fatalf("no %q signal", id)
+	return MaturitySignal{}
+}
+
+// authoredCommits spreads commits by authors evenly over days, newest first
+func authoredCommits(days int, authors ...string) []github.Commit {
+	commits := make([]github.Commit, len(authors))
+	for i, a := range authors {
+		commits[i].Commit.Author.Name = a
+		commits[i].Commit.Author.Date = maturityNow.AddDate(0, 0, -days*i/(len(authors)-1))
+	}
+	return commits
+}
+
+func files(community []string, tests int) *FileStats {
+	s := &FileStats{Tests: []LanguageTests{{Language: "Go", Tests: tests}}}
+	for _, name := range community {
+		s.Community = append(s.Community, CommunityFile{Name: name, Path: name})
+	}
+	return s
+}
+
+func TestMaturitySignals(t *testing.T) {
+	tests := []struct {
+		name      string
+		signal    string
+		in        MaturityInput
+		wantScore int
+		wantKnown bool
+	}{
+		{"age without repository", "age", MaturityInput{}, 0, false},
+		{"young repository", "age", MaturityInput{Repo: &github.Repo{CreatedAt: maturityNow.AddDate(0, 0, -219)}}, 20, true},
+		{"mature age caps", "age", MaturityInput{Repo: &github.Repo{CreatedAt: maturityNow.AddDate(-10, 0, 0)}}, 100, true},
+
+		{"small backlog", "issue_load", MaturityInput{Repo: &github.Repo{OpenIssues: 1}}, 100, true},
+		{"backlog at the bad ratio", "issue_load", MaturityInput{Repo: &github.Repo{OpenIssues: 10}}, 0, true},
+		{"backlog past the bad ratio clamps", "issue_load", MaturityInput{Repo: &github.Repo{OpenIssues: 500}}, 0, true},
+		{"backlog scales with stars", "issue_load", MaturityInput{Repo: &github.Repo{OpenIssues: 110, Stars: 950}}, 50, true},
+
+		{"close rate without issues", "issue_close_rate", MaturityInput{Issues: &IssueHealth{}}, 0, false},
+		{"half the target close rate", "issue_close_rate", MaturityInput{Issues: &IssueHealth{Total: 10, Closed: 4}}, 50, true},
+		{"close rate above target caps", "issue_close_rate", MaturityInput{Issues: &IssueHealth{Total: 10, Closed: 10}}, 100, true},
+
+		{"releases not fetched", "releases", MaturityInput{}, 0, false},
+		{"no releases", "releases", MaturityInput{Releases: &ReleaseStats{DaysSinceLastRelease: -1}}, 0, true},
+		{"one tag", "releases", MaturityInput{Releases: &ReleaseStats{Tags: 1, DaysSinceLastRelease: -1}}, 55, true},
+		{"old releases", "releases", MaturityInput{Releases: &ReleaseStats{Releases: 2, DaysSinceLastRelease: 400}}, 60, true},
+		{"regular recent releases", "releases", MaturityInput{Releases: &ReleaseStats{Releases: 8, Tags: 8, DaysSinceLastRelease: 30}}, 100, true},
+
+		{"too few commits for growth", "contributor_growth", MaturityInput{Commits: authoredCommits(100, "a", "b", "c")}, 0, false},
+		{"history too short for growth", "contributor_growth", MaturityInput{Commits: authoredCommits(10, "a", "a", "a", "a", "a", "b", "b", "b", "b", "b")}, 0, false},
+		{"contributors halved", "contributor_growth", MaturityInput{Commits: authoredCommits(100, "a", "a", "a", "a", "a", "a", "b", "a", "b", "a")}, 50, true},
+		{"contributors grew", "contributor_growth", MaturityInput{Commits: authoredCommits(100, "a", "b", "c", "a", "b", "a", "a", "a", "a", "a")}, 100, true},
+
+		{"documentation not fetched", "documentation", MaturityInput{}, 0, false},
+		{"readme and license", "documentation", MaturityInput{Files: files([]string{FileReadme, FileLicense}, 0)}, 70, true},
+		{"all documentation", "documentation", MaturityInput{Files: files([]string{FileReadme, FileLicense, FileContributing, FileChangelog}, 0)}, 100, true},
+
+		{"ci without tests", "ci", MaturityInput{Files: files([]string{FileCI}, 0)}, 70, true},
+		{"tests without ci", "ci", MaturityInput{Files: files(nil, 3)}, 30, true},
+		{"nothing to check in the tree", "ci", MaturityInput{Files: files(nil, 0)}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.in.Now = maturityNow
+			s := signal(t, AssessMaturity(tt.in), tt.signal)
+			if s.Known != tt.wantKnown || s.Score != tt.wantScore {
+				t.Errorf("signal = %+v, want score %d known %v", s, tt.wantScore, tt.wantKnown)
+			}
+			if !s.Known && s.Detail != "not checked" {
+				t.Errorf("unknown signal detail = %q", s.Detail)
+			}
+		})
+	}
+}
+
+func TestAssessMaturityConfidence(t *testing.T) {
+	repo := &github.Repo{CreatedAt: maturityNow.AddDate(-5, 0, 0), OpenIssues: 10}
+
+	t.Run("nothing known", func(t *testing.T) {
+		m := AssessMaturity(MaturityInput{Now: maturityNow})
+		if m.Level != "Unknown" || m.Score != 0 || m.Confidence != 0 || m.ConfidenceLabel() != "low" {
+			t.Errorf("maturity = %+v", m)
+		}
+		if len(m.Signals) != len(maturitySignals) {
+			t.Errorf("got %d signals, want every signal listed", len(m.Signals))
+		}
+	})
+
+	t.Run("repository only", func(t *testing.T) {
+		// Age scores 100 and the backlog 0, each weighing 15 of 100
+		m := AssessMaturity(MaturityInput{Repo: repo, Now: maturityNow})
+		if m.Score != 50 || m.Confidence != 0.3 || m.ConfidenceLabel() != "low" || m.Level != "Growing" {
+			t.Errorf("maturity = %d %s at %v (%s)", m.Score, m.Level, m.Confidence, m.ConfidenceLabel())
+		}
+	})
+
+	t.Run("repository and tree", func(t *testing.T) {
+		m := AssessMaturity(MaturityInput{Repo: repo, Files: files([]string{FileReadme, FileLicense, FileContributing, FileChangelog, FileCI}, 4), Now: maturityNow})
+		// (100 + 0 + 100 + 100) * 15 / 60
+		if m.Score != 75 || m.Confidence != 0.6 || m.ConfidenceLabel() != "medium" {
+			t.Errorf("maturity = %d at %v (%s)", m.Score, m.Confidence, m.ConfidenceLabel())
+		}
+	})
+
+	t.Run("everything known", func(t *testing.T) {
+		m := AssessMaturity(MaturityInput{
+			Repo:     &github.Repo{CreatedAt: maturityNow.AddDate(-5, 0, 0), Stars: 1000},
+			Commits:  authoredCommits(100, "a", "b", "a", "b", "a", "b", "a", "b", "a", "b"),
+			Releases: &ReleaseStats{Releases: 10, DaysSinceLastRelease: 10},
+			Issues:   &IssueHealth{Total: 10, Closed: 9},
+			Files:    files([]string{FileReadme, FileLicense, FileContributing, FileChangelog, FileCI}, 4),
+			Now:      maturityNow,
+		})
+		if m.Score != 100 || m.Confidence != 1 || m.ConfidenceLabel() != "high" || m.Level != "Production-Ready" {
+			t.Errorf("maturity = %d %s at %v", m.Score, m.Level, m.Confidence)
+		}
+	})
+}
+
+func TestMaturityWeightsAddUp(t *testing.T) {
+	total := 0.0
+	for _, s := range maturitySignals {
+		total += s.weight
+	}
+	if total != 100 {
+		t.Errorf("signal weights add up to %v, want 100", total)
+	}
+}
+
+func TestMaturityLevel(t *testing.T) {
+	for score, want := range map[int]string{0: "Prototype", 39: "Prototype", 40: "Growing", 60: "Stable", 79: "Stable", 80: "Production-Ready", 100: "Production-Ready"} {
+		if got := maturityLevel(score); got != want {
+			t.Errorf("maturityLevel(%d) = %q, want %q", score, got, want)
+		}
+	}
+	for confidence, want := range map[float64]string{0.49: "low", 0.5: "medium", 0.79: "medium", 0.8: "high"} {
+		if got := (Maturity{Confidence: confidence}).ConfidenceLabel(); got != want {
+			t.Errorf("ConfidenceLabel at %v = %q, want %q", confidence, got, want)
+		}
+	}
+}
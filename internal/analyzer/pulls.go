@@ -0,0 +1,245 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// SizeBucket counts pull requests whose changed lines fall under Max
+// (0 for the open-ended last bucket)
+type SizeBucket struct {
+	Label string
+	Max   int
+	Count int
+}
+
+// prSizeBuckets are the size classes used for the distribution
+var prSizeBuckets = []SizeBucket{
+	{Label: "XS", Max: 10},
+	{Label: "S", Max: 50},
+	{Label: "M", Max: 250},
+	{Label: "L", Max: 1000},
+	{Label: "XL"},
+}
+
+// PRHealth summarises how pull requests are reviewed and merged
+type PRHealth struct {
+	Total             int
+	Open              int
+	Merged            int
+	ClosedUnmerged    int
+	MergeRate         float64 // merged share of closed pull requests
+	MedianTimeToMerge time.Duration
+	MedianFirstReview time.Duration
+	Reviewed          int // pull requests with a review or review comment from someone other than the author
+	// Sampled pull requests had their size and reviews fetched; review
+	// coverage and the size distribution are computed over them only
+	Sampled        int
+	SampledMerged  int
+	ReviewCoverage float64 // share of sampled merged pull requests with an approving review
+	Sizes          []SizeBucket
+	// ReviewPartial is set when the review comment listing stopped short;
+	// older pull requests then only count the reviews of the sample
+	ReviewPartial bool
+
+	External           int // closed pull requests from authors outside the project
+	ExternalMerged     int
+	ExternalAcceptance float64
+
+	Score   int
+	Grade   string
+	Verdict string
+}
+
+// Summary is the one-line verdict used in recruiter output
+func (h PRHealth) Summary() string {
+	if h.Total == 0 {
+		return "No pull requests"
+	}
+	return fmt.Sprintf("%s (%s)", h.Grade, h.Verdict)
+}
+
+// AnalyzePullRequests grades how pull requests are handled. reviews holds
+// the reviews of the sampled pull requests and sized their full records
+// (with line counts), both keyed by number. comments is a repository-wide
+// listing of inline review comments, newest first; unless complete, only
+// those on pull requests created after its oldest comment are used, so a
+// cut-short listing cannot make first reviews look later than they were.
+func AnalyzePullRequests(pulls []github.PullRequest, sized map[int]github.PullRequest, reviews map[int][]github.Review, comments []github.ReviewComment, complete bool) PRHealth {
+	h := PRHealth{Grade: "-", Verdict: "No pull requests"}
+	for _, b := range prSizeBuckets {
+		h.Sizes = append(h.Sizes, SizeBucket{Label: b.Label, Max: b.Max})
+	}
+	if len(pulls) == 0 {
+		return h
+	}
+
+	byNumber := make(map[int]github.PullRequest, len(pulls))
+	var mergeTimes []time.Duration
+	for _, pr := range pulls {
+		byNumber[pr.Number] = pr
+
+		external := isExternal(pr)
+		switch {
+		case pr.Merged():
+			h.Merged++
+			mergeTimes = append(mergeTimes, pr.MergedAt.Sub(pr.CreatedAt))
+			if external {
+				h.External++
+				h.ExternalMerged++
+			}
+		case pr.State == "closed":
+			h.ClosedUnmerged++
+			if external {
+				h.External++
+			}
+		default:
+			h.Open++
+		}
+	}
+	h.Total = len(pulls)
+
+	// First review is the earliest review or inline comment by someone else
+	firstReview := map[int]time.Time{}
+	consider := func(number int, login, kind string, at time.Time) {
+		pr, ok := byNumber[number]
+		if !ok || login == pr.User.Login || kind == "Bot" || at.IsZero() {
+			return
+		}
+		if prev, seen := firstReview[number]; !seen || at.Before(prev) {
+			firstReview[number] = at
+		}
+	}
+	for number, rs := range reviews {
+		for _, r := range rs {
+			consider(number, r.User.Login, r.User.Type, r.SubmittedAt)
+		}
+	}
+	created := make([]time.Time, len(comments))
+	for i, c := range comments {
+		created[i] = c.CreatedAt
+	}
+	covered := commentCoverage(created, complete)
+	h.ReviewPartial = !complete
+	for _, c := range comments {
+		if pr, ok := byNumber[c.PullNumber()]; ok && covered(pr.CreatedAt) {
+			consider(pr.Number, c.User.Login, c.User.Type, c.CreatedAt)
+		}
+	}
+	reviewTimes := make([]time.Duration, 0, len(firstReview))
+	for number, at := range firstReview {
+		reviewTimes = append(reviewTimes, at.Sub(byNumber[number].CreatedAt))
+	}
+	h.Reviewed = len(reviewTimes)
+
+	var approved int
+	for number, rs := range reviews {
+		pr, ok := byNumber[number]
+		if !ok {
+			continue
+		}
+		h.Sampled++
+		if full, ok := sized[number]; ok {
+			h.Sizes[sizeBucket(full.Additions+full.Deletions)].Count++
+		}
+		if !pr.Merged() {
+			continue
+		}
+		h.SampledMerged++
+		for _, r := range rs {
+			if r.State == "APPROVED" && r.User.Login != pr.User.Login {
+				approved++
+				break
+			}
+		}
+	}
+
+	if decided := h.Merged + h.ClosedUnmerged; decided > 0 {
+		h.MergeRate = float64(h.Merged) / float64(decided)
+	}
+	if h.SampledMerged > 0 {
+		h.ReviewCoverage = float64(approved) / float64(h.SampledMerged)
+	}
+	if h.External > 0 {
+		h.ExternalAcceptance = float64(h.ExternalMerged) / float64(h.External)
+	}
+	h.MedianTimeToMerge = medianDuration(mergeTimes)
+	h.MedianFirstReview = medianDuration(reviewTimes)
+
+	h.Score = prScore(h)
+	h.Grade, h.Verdict = healthGrade(h.Score)
+	return h
+}
+
+// isExternal reports whether a pull request comes from outside the
+// project's owners, members and collaborators. Bots are not counted.
+func isExternal(pr github.PullRequest) bool {
+	if pr.User.Type == "Bot" {
+		return false
+	}
+	switch pr.AuthorAssociation {
+	case "OWNER", "MEMBER", "COLLABORATOR":
+		return false
+	}
+	return true
+}
+
+func sizeBucket(lines int) int {
+	for i, b := range prSizeBuckets {
+		if b.Max == 0 || lines < b.Max {
+			return i
+		}
+	}
+	return len(prSizeBuckets) - 1
+}
+
+// prScore weighs merge rate (30), time to merge (20), time to first review
+// (20), review coverage (20) and external acceptance (10). Without a review
+// sample the review parts are unknown and the rest is scaled up to 100.
+func prScore(h PRHealth) int {
+	score := 30 * h.MergeRate
+	available := 100.0
+
+	day := 24 * time.Hour
+	switch {
+	case h.Merged == 0:
+	case h.MedianTimeToMerge <= 2*day:
+		score += 20
+	case h.MedianTimeToMerge <= 7*day:
+		score += 14
+	case h.MedianTimeToMerge <= 30*day:
+		score += 8
+	default:
+		score += 3
+	}
+
+	switch {
+	case h.Sampled == 0:
+		available -= 20
+	case h.Reviewed == 0:
+	case h.MedianFirstReview <= day:
+		score += 20
+	case h.MedianFirstReview <= 3*day:
+		score += 14
+	case h.MedianFirstReview <= 14*day:
+		score += 8
+	default:
+		score += 3
+	}
+
+	if h.SampledMerged == 0 {
+		available -= 20
+	} else {
+		score += 20 * h.ReviewCoverage
+	}
+
+	if h.External == 0 {
+		// Nobody outside the project contributes; neither good nor bad
+		score += 5
+	} else {
+		score += 10 * h.ExternalAcceptance
+	}
+	return int(score*100/available + 0.5)
+}
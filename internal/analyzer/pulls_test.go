@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func testPull(number int, created time.Time) github.PullRequest {
+	merged := created.Add(12 * time.Hour)
+	pr := github.PullRequest{Number: number, State: "closed", AuthorAssociation: "MEMBER", CreatedAt: created, MergedAt: &merged}
+	pr.User.Login = "author"
+	return pr
+}
+
+func testReviewComment(number int, created time.Time) github.ReviewComment {
+	return github.ReviewComment{
+		PullRequestURL: fmt.Sprintf("https://api.github.com/repos/o/r/pulls/%d", number),
+		User:           github.User{Login: "reviewer", Type: "User"},
+		CreatedAt:      created,
+	}
+}
+
+func TestPRScoreWithoutSample(t *testing.T) {
+	// Every pull request merged within a day, none from outside: 30 + 20 + 5
+	h := PRHealth{Total: 4, Merged: 4, MergeRate: 1, MedianTimeToMerge: time.Hour}
+	if got := prScore(h); got != 92 {
+		t.Errorf("score without a review sample = %d, want 92 (55 of 60)", got)
+	}
+
+	// Sampled, but only unmerged pull requests: coverage stays unknown
+	h.Sampled = 2
+	if got := prScore(h); got != 69 {
+		t.Errorf("score with no reviews and no merged sample = %d, want 69 (55 of 80)", got)
+	}
+
+	h.SampledMerged, h.ReviewCoverage = 2, 1
+	h.Reviewed, h.MedianFirstReview = 2, time.Hour
+	if got := prScore(h); got != 95 {
+		t.Errorf("score with a full sample = %d, want 95", got)
+	}
+}
+
+func TestAnalyzePullRequestsCommentCoverage(t *testing.T) {
+	base := time.Now().Add(-10 * 24 * time.Hour)
+	pulls := []github.PullRequest{
+		testPull(3, base.Add(8*24*time.Hour)),
+		testPull(2, base.Add(5*24*time.Hour)),
+		testPull(1, base),
+	}
+	comments := []github.ReviewComment{
+		testReviewComment(3, base.Add(8*24*time.Hour+time.Hour)),
+		testReviewComment(2, base.Add(5*24*time.Hour+2*time.Hour)),
+		testReviewComment(1, base.Add(3*24*time.Hour)),
+	}
+
+	h := AnalyzePullRequests(pulls, nil, nil, comments, true)
+	if h.Reviewed != 3 || h.MedianFirstReview != 2*time.Hour || h.ReviewPartial {
+		t.Errorf("complete: reviewed, first review, partial = %d, %v, %v, want 3, 2h, false", h.Reviewed, h.MedianFirstReview, h.ReviewPartial)
+	}
+
+	// Capped after two comments: only #3 was opened after the oldest one
+	// fetched, so #2 may have had earlier comments that were cut off
+	h = AnalyzePullRequests(pulls, nil, nil, comments[:2], false)
+	if h.Reviewed != 1 || h.MedianFirstReview != time.Hour || !h.ReviewPartial {
+		t.Errorf("capped: reviewed, first review, partial = %d, %v, %v, want 1, 1h, true", h.Reviewed, h.MedianFirstReview, h.ReviewPartial)
+	}
+	if h.Sampled != 0 || h.SampledMerged != 0 {
+		t.Errorf("Sampled, SampledMerged = %d, %d without details", h.Sampled, h.SampledMerged)
+	}
+}
@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// semverTag matches "1.2.3", "v1.2.3", "v1.2.3-rc.1+build.5".
+var semverTag = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// ReleaseStats summarises a repository's release history
+type ReleaseStats struct {
+	Releases             int
+	Tags                 int
+	PreReleases          int
+	PreReleaseRatio      float64
+	LastRelease          string // tag of the newest published release
+	LastReleaseAt        time.Time
+	DaysSinceLastRelease int     // -1 when there are no releases
+	AvgDaysBetween       float64 // mean gap between consecutive releases
+	Cadence              string
+	SemverTags           int
+	SemverCompliance     float64 // share of tag names that are valid semver
+	TotalAssets          int
+}
+
+// HasReleases reports whether the project ships versions at all, either
+// as GitHub releases or plain tags
+func (s ReleaseStats) HasReleases() bool {
+	return s.Releases > 0 || s.Tags > 0
+}
+
+// AnalyzeReleases computes cadence, recency, pre-release ratio, semver
+// compliance and asset counts as of now. Drafts are ignored.
+func AnalyzeReleases(releases []github.Release, tags []github.Tag, now time.Time) ReleaseStats {
+	stats := ReleaseStats{DaysSinceLastRelease: -1, Cadence: "None"}
+
+	var published []github.Release
+	for _, r := range releases {
+		if !r.Draft {
+			published = append(published, r)
+		}
+	}
+	sort.Slice(published, func(i, j int) bool {
+		return releaseTime(published[i]).After(releaseTime(published[j]))
+	})
+
+	stats.Releases = len(published)
+	stats.Tags = len(tags)
+
+	for _, r := range published {
+		if r.Prerelease {
+			stats.PreReleases++
+		}
+		stats.TotalAssets += len(r.Assets)
+	}
+
+	if stats.Releases > 0 {
+		stats.PreReleaseRatio = float64(stats.PreReleases) / float64(stats.Releases)
+		stats.LastRelease = published[0].TagName
+		stats.LastReleaseAt = releaseTime(published[0])
+		stats.DaysSinceLastRelease = int(now.Sub(stats.LastReleaseAt).Hours() / 24)
+	}
+
+	if stats.Releases > 1 {
+		span := releaseTime(published[0]).Sub(releaseTime(published[len(published)-1]))
+		stats.AvgDaysBetween = span.Hours() / 24 / float64(stats.Releases-1)
+	}
+	stats.Cadence = releaseCadence(stats)
+
+	// Judge naming on tags; fall back to release tags when none were listed
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	if len(names) == 0 {
+		for _, r := range published {
+			names = append(names, r.TagName)
+		}
+	}
+	for _, name := range names {
+		if semverTag.MatchString(name) {
+			stats.SemverTags++
+		}
+	}
+	if len(names) > 0 {
+		stats.SemverCompliance = float64(stats.SemverTags) / float64(len(names))
+	}
+
+	return stats
+}
+
+func releaseTime(r github.Release) time.Time {
+	if !r.PublishedAt.IsZero() {
+		return r.PublishedAt
+	}
+	return r.CreatedAt
+}
+
+func releaseCadence(s ReleaseStats) string {
+	switch {
+	case s.Releases == 0:
+		return "None"
+	case s.DaysSinceLastRelease > 365:
+		return "Dormant"
+	case s.Releases == 1:
+		return "Single Release"
+	case s.AvgDaysBetween <= 14:
+		return "Frequent"
+	case s.AvgDaysBetween <= 60:
+		return "Regular"
+	case s.AvgDaysBetween <= 180:
+		return "Occasional"
+	default:
+		return "Rare"
+	}
+}
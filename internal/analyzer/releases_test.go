@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+var releaseNow = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func release(tag string, daysAgo int, opts ...func(*github.Release)) github.Release {
+	r := github.Release{TagName: tag, PublishedAt: releaseNow.AddDate(0, 0, -daysAgo)}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+func draft(r *github.Release)      { r.Draft = true }
+func prerelease(r *github.Release) { r.Prerelease = true }
+
+func TestSemverTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"1.2.3", true},
+		{"v1.2.3", true},
+		{"v0.0.1", true},
+		{"v1.2.3-rc.1", true},
+		{"v1.2.3-rc.1+build.5", true},
+		{"1.2.3+meta", true},
+		{"v10.20.30", true},
+		{"v1.2", false},
+		{"1", false},
+		{"V1.2.3", false},
+		{"v01.2.3", false},
+		{"v1.2.3.4", false},
+		{"release-1.2.3", false},
+		{"v1.2.3-", false},
+		{"latest", false},
+	}
+	for _, tt := range tests {
+		if got := semverTag.MatchString(tt.tag); got != tt.want {
+			t.Errorf("semverTag(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeReleases(t *testing.T) {
+	releases := []github.Release{
+		release("v1.0.0", 100),
+		release("v2.0.0-beta", 40, prerelease),
+		release("v2.0.0", 10, func(r *github.Release) { r.Assets = make([]github.ReleaseAsset, 3) }),
+		release("v3.0.0", 1, draft),
+		// Unpublished releases fall back to their creation date
+		{TagName: "v0.1.0", CreatedAt: releaseNow.AddDate(0, 0, -190)},
+	}
+	s := AnalyzeReleases(releases, nil, releaseNow)
+
+	if s.Releases != 4 {
+		t.Errorf("Releases = %d, want 4 without the draft", s.Releases)
+	}
+	if s.LastRelease != "v2.0.0" || s.DaysSinceLastRelease != 10 || !s.LastReleaseAt.Equal(releaseNow.AddDate(0, 0, -10)) {
+		t.Errorf("last release = %s, %d days ago", s.LastRelease, s.DaysSinceLastRelease)
+	}
+	if s.PreReleases != 1 || s.PreReleaseRatio != 0.25 {
+		t.Errorf("pre-releases = %d (%v), want 1 (0.25)", s.PreReleases, s.PreReleaseRatio)
+	}
+	// 180 days between the oldest and newest over three gaps
+	if s.AvgDaysBetween != 60 {
+		t.Errorf("AvgDaysBetween = %v, want 60", s.AvgDaysBetween)
+	}
+	if s.Cadence != "Regular" {
+		t.Errorf("Cadence = %q, want Regular", s.Cadence)
+	}
+	if s.TotalAssets != 3 {
+		t.Errorf("TotalAssets = %d, want 3", s.TotalAssets)
+	}
+	// No tags listed, so the release tags are judged: all four are semver
+	if s.SemverTags != 4 || s.SemverCompliance != 1 {
+		t.Errorf("semver = %d (%v), want 4 (1)", s.SemverTags, s.SemverCompliance)
+	}
+}
+
+func TestAnalyzeReleasesTags(t *testing.T) {
+	tags := []github.Tag{{Name: "v1.0.0"}, {Name: "v1.1"}, {Name: "nightly"}, {Name: "2.0.0"}}
+	s := AnalyzeReleases([]github.Release{release("v9.9.9", 5)}, tags, releaseNow)
+	// Tags take precedence over release names
+	if s.Tags != 4 || s.SemverTags != 2 || s.SemverCompliance != 0.5 {
+		t.Errorf("tags = %d, semver %d (%v)", s.Tags, s.SemverTags, s.SemverCompliance)
+	}
+	if s.Cadence != "Single Release" || s.AvgDaysBetween != 0 {
+		t.Errorf("Cadence = %q, AvgDaysBetween = %v", s.Cadence, s.AvgDaysBetween)
+	}
+	if !s.HasReleases() {
+		t.Error("HasReleases = false")
+	}
+}
+
+func TestAnalyzeReleasesNone(t *testing.T) {
+	s := AnalyzeReleases([]github.Release{release("v1.0.0", 1, draft)}, nil, releaseNow)
+	if s.Releases != 0 || s.DaysSinceLastRelease != -1 || s.Cadence != "None" || s.HasReleases() {
+		t.Errorf("drafts only = %+v", s)
+	}
+	if s.SemverCompliance != 0 || math.IsNaN(s.PreReleaseRatio) {
+		t.Errorf("ratios without releases = %v, %v", s.SemverCompliance, s.PreReleaseRatio)
+	}
+}
+
+func TestReleaseCadence(t *testing.T) {
+	tests := []struct {
+		name     string
+		releases []github.Release
+		want     string
+	}{
+		{"weekly", []github.Release{release("a", 1), release("b", 8), release("c", 15)}, "Frequent"},
+		{"quarterly", []github.Release{release("a", 10), release("b", 100)}, "Occasional"},
+		{"yearly", []github.Release{release("a", 10), release("b", 300)}, "Rare"},
+		{"dormant", []github.Release{release("a", 400), release("b", 410)}, "Dormant"},
+	}
+	for _, tt := range tests {
+		if got := AnalyzeReleases(tt.releases, nil, releaseNow).Cadence; got != tt.want {
+			t.Errorf("%s: Cadence = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
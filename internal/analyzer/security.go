@@ -1 +1,288 @@
-package analyzer
\ No newline at end of file
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// Finding severities
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// SecurityCheck is one item of the security checklist. Checks whose data
+// could not be fetched are Skipped and left out of the score.
+type SecurityCheck struct {
+	Name      string
+	Passed    bool
+	Skipped   bool
+	Points    int
+	MaxPoints int
+	Detail    string
+}
+
+// SecurityFinding is a concrete problem worth looking at
+type SecurityFinding struct {
+	Severity string
+	File     string
+	Message  string
+}
+
+// SecurityReport is the scored checklist plus findings
+type SecurityReport struct {
+	Score    int
+	Grade    string
+	Verdict  string
+	Checks   []SecurityCheck
+	Findings []SecurityFinding
+
+	CommitsChecked  int
+	SignedCommits   int
+	SignedRatio     float64
+	PinnedActions   int
+	UnpinnedActions int
+}
+
+// securityPolicyPaths are where GitHub looks for a security policy
+var securityPolicyPaths = []string{"SECURITY.md", ".github/SECURITY.md", "docs/SECURITY.md"}
+
+// dependencyBotPaths configure Dependabot or Renovate
+var dependencyBotPaths = []string{
+	".github/dependabot.yml", ".github/dependabot.yaml",
+	"renovate.json", "renovate.json5", ".renovaterc", ".renovaterc.json",
+	".github/renovate.json", ".github/renovate.json5",
+}
+
+// scanningActions are actions that scan code or dependencies
+var scanningActions = []string{
+	"github/codeql-action", "ossf/scorecard-action", "aquasecurity/trivy-action",
+	"snyk/actions", "securego/gosec", "anchore/scan-action",
+	"actions/dependency-review-action", "semgrep/semgrep-action", "returntocorp/semgrep-action",
+}
+
+// secretNames and secretExts look like credentials that should not be committed
+var (
+	secretNames = map[string]bool{
+		".env": true, "id_rsa": true, "id_dsa": true, "id_ecdsa": true, "id_ed25519": true,
+		"credentials.json": true, ".netrc": true, ".pgpass": true, ".htpasswd": true,
+		"secrets.yml": true, "secrets.yaml": true, "service-account.json": true,
+	}
+	secretExts = map[string]bool{".pem": true, ".key": true, ".p12": true, ".pfx": true, ".keystore": true, ".jks": true}
+	// Templates and samples of the above are fine
+	secretSafeSuffixes = []string{".example", ".sample", ".template", ".dist", ".pub"}
+)
+
+var usesLine = regexp.MustCompile(`(?m)^\s*-?\s*uses:\s*["']?([^\s"'#]+)`)
+var fullSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// WorkflowPaths returns the GitHub Actions workflow files in tree
+func WorkflowPaths(tree []github.TreeEntry) []string {
+	var paths []string
+	for _, e := range tree {
+		if e.Type != "blob" || path.Dir(e.Path) != ".github/workflows" {
+			continue
+		}
+		if ext := path.Ext(e.Path); ext == ".yml" || ext == ".yaml" {
+			paths = append(paths, e.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// AnalyzeSecurity scores the security posture from the file tree, the
+// commits of the window, the default branch and the workflow files keyed by
+// path. A nil tree, commit list, branch or workflows map skips the checks
+// that need them; an empty commit list is a window without commits.
+func AnalyzeSecurity(tree []github.TreeEntry, commits []github.Commit, branch *github.Branch, workflows map[string][]byte) SecurityReport {
+	var r SecurityReport
+
+	files := map[string]bool{}
+	for _, e := range tree {
+		if e.Type == "blob" {
+			files[e.Path] = true
+		}
+	}
+	haveTree := tree != nil
+
+	// Security policy
+	policy := SecurityCheck{Name: "Security policy (SECURITY.md)", MaxPoints: 15, Skipped: !haveTree}
+	if p := firstPresent(files, securityPolicyPaths); p != "" {
+		policy.Passed, policy.Detail = true, p
+	} else if haveTree {
+		policy.Detail = "no SECURITY.md"
+	}
+	r.Checks = append(r.Checks, policy)
+
+	// Automated dependency updates
+	deps := SecurityCheck{Name: "Dependency updates (Dependabot/Renovate)", MaxPoints: 15, Skipped: !haveTree}
+	if p := firstPresent(files, dependencyBotPaths); p != "" {
+		deps.Passed, deps.Detail = true, p
+	} else if haveTree {
+		deps.Detail = "no Dependabot or Renovate config"
+	}
+	r.Checks = append(r.Checks, deps)
+
+	// Code scanning and action pinning, both read from workflows
+	haveWorkflows := haveTree && (workflows != nil || len(WorkflowPaths(tree)) == 0)
+	scanning := SecurityCheck{Name: "Code scanning workflow", MaxPoints: 20, Skipped: !haveWorkflows}
+	pinning := SecurityCheck{Name: "Actions pinned to commit SHAs", MaxPoints: 10, Skipped: !haveWorkflows}
+	paths := make([]string, 0, len(workflows))
+	for p := range workflows {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		for _, m := range usesLine.FindAllStringSubmatch(string(workflows[p]), -1) {
+			action := m[1]
+			if strings.HasPrefix(action, "./") || strings.HasPrefix(action, "docker://") {
+				continue
+			}
+			for _, s := range scanningActions {
+				if strings.HasPrefix(action, s) && !scanning.Passed {
+					scanning.Passed, scanning.Detail = true, fmt.Sprintf("%s in %s", s, p)
+				}
+			}
+			if at := strings.LastIndex(action, "@"); at >= 0 && fullSHA.MatchString(action[at+1:]) {
+				r.PinnedActions++
+				continue
+			}
+			r.UnpinnedActions++
+			r.Findings = append(r.Findings, SecurityFinding{
+				Severity: SeverityLow,
+				File:     p,
+				Message:  fmt.Sprintf("action %s is not pinned to a commit SHA", action),
+			})
+		}
+	}
+	if !scanning.Passed && haveWorkflows {
+		scanning.Detail = "no CodeQL or other scanning action in workflows"
+	}
+	if total := r.PinnedActions + r.UnpinnedActions; total > 0 {
+		pinning.Points = pinning.MaxPoints * r.PinnedActions / total
+		pinning.Passed = r.UnpinnedActions == 0
+		pinning.Detail = fmt.Sprintf("%d of %d action references pinned", r.PinnedActions, total)
+	} else if haveWorkflows {
+		pinning.Passed, pinning.Detail = true, "no third-party actions used"
+	}
+	r.Checks = append(r.Checks, scanning)
+
+	// Branch protection
+	protection := SecurityCheck{Name: "Default branch protected", MaxPoints: 20, Skipped: branch == nil}
+	if branch != nil {
+		protection.Passed = branch.Protected
+		protection.Detail = branch.Name
+		if !branch.Protected {
+			protection.Detail += " is not protected"
+		}
+	}
+	r.Checks = append(r.Checks, protection)
+
+	// Signed commits
+	signed := SecurityCheck{Name: "Signed commits", MaxPoints: 10, Skipped: commits == nil}
+	for _, c := range commits {
+		r.CommitsChecked++
+		if c.Commit.Verification.Verified {
+			r.SignedCommits++
+		}
+	}
+	if r.CommitsChecked > 0 {
+		r.SignedRatio = float64(r.SignedCommits) / float64(r.CommitsChecked)
+		signed.Points = int(float64(signed.MaxPoints)*r.SignedRatio + 0.5)
+		signed.Passed = r.SignedRatio >= 0.5
+		signed.Detail = fmt.Sprintf("%d of %d commits verified (%.0f%%)", r.SignedCommits, r.CommitsChecked, r.SignedRatio*100)
+	} else if commits != nil {
+		signed.Detail = "no commits in the window"
+	}
+	r.Checks = append(r.Checks, signed)
+
+	// Secret-looking files
+	secrets := SecurityCheck{Name: "No secret-looking files committed", MaxPoints: 10, Skipped: !haveTree}
+	for _, e := range tree {
+		if e.Type == "blob" && looksLikeSecret(e.Path) {
+			r.Findings = append(r.Findings, SecurityFinding{
+				Severity: SeverityHigh,
+				File:     e.Path,
+				Message:  "file name suggests committed credentials",
+			})
+		}
+	}
+	if haveTree {
+		secrets.Passed = true
+		for _, f := range r.Findings {
+			if f.Severity == SeverityHigh {
+				secrets.Passed = false
+				secrets.Detail = f.File
+				break
+			}
+		}
+	}
+	r.Checks = append(r.Checks, secrets, pinning)
+
+	// Full points for passed checks unless the check scored partially
+	scored, max := 0, 0
+	for i := range r.Checks {
+		c := &r.Checks[i]
+		if c.Skipped {
+			continue
+		}
+		if c.Passed && c.Points == 0 {
+			c.Points = c.MaxPoints
+		}
+		scored += c.Points
+		max += c.MaxPoints
+	}
+	if max > 0 {
+		r.Score = scored * 100 / max
+	}
+	r.Grade, r.Verdict = healthGrade(r.Score)
+	if max == 0 {
+		r.Grade, r.Verdict = "-", "Unknown"
+	}
+
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		return severityRank(r.Findings[i].Severity) < severityRank(r.Findings[j].Severity)
+	})
+	return r
+}
+
+func firstPresent(files map[string]bool, candidates []string) string {
+	for _, p := range candidates {
+		if files[p] {
+			return p
+		}
+	}
+	return ""
+}
+
+func looksLikeSecret(p string) bool {
+	name := strings.ToLower(path.Base(p))
+	for _, suffix := range secretSafeSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	if secretNames[name] || secretExts[path.Ext(name)] {
+		return true
+	}
+	// .env.production, .env.local and friends
+	return strings.HasPrefix(name, ".env.")
+}
+
+func severityRank(s string) int {
+	switch s {
+	case SeverityHigh:
+		return 0
+	case SeverityMedium:
+		return 1
+	default:
+		return 2
+	}
+}
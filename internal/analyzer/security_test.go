@@ -0,0 +1,208 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestLooksLikeSecret(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{".env", true},
+		{"config/.env", true},
+		{".env.local", true},
+		{".env.production", true},
+		{".env.example", false},
+		{".env.sample", false},
+		{"id_rsa", true},
+		{"home/.ssh/id_ed25519", true},
+		{"id_rsa.pub", false},
+		{"certs/server.pem", true},
+		{"certs/SERVER.PEM", true},
+		{"tls.key", true},
+		{"server.pem.template", false},
+		{"environment.go", false},
+		{".envrc", false},
+		{"docs/keys.md", false},
+	}
+	for _, tt := range tests {
+		if got := looksLikeSecret(tt.path); got != tt.want {
+			t.Errorf("looksLikeSecret(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestUsesLine(t *testing.T) {
+	workflow := `
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11
+      - uses: actions/setup-go@v5
+      - name: local
+        uses: ./.github/actions/build
+      -   uses: docker://alpine:3.19
+      - uses: "github/codeql-action/init@v3"
+      - uses: 'snyk/actions/golang@master' # trailing comment
+      - run: echo "uses: not/an-action@v1"
+`
+	var got []string
+	for _, m := range usesLine.FindAllStringSubmatch(workflow, -1) {
+		got = append(got, m[1])
+	}
+	want := []string{
+		"actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11",
+		"actions/setup-go@v5",
+		"./.github/actions/build",
+		"docker://alpine:3.19",
+		"github/codeql-action/init@v3",
+		"snyk/actions/golang@master",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("uses values = %q, want %q", got, want)
+	}
+
+	r := AnalyzeSecurity([]github.TreeEntry{{Path: ".github/workflows/ci.yml", Type: "blob"}}, nil, nil,
+		map[string][]byte{".github/workflows/ci.yml": []byte(workflow)})
+	// Local and docker references are neither pinned nor unpinned
+	if r.PinnedActions != 1 || r.UnpinnedActions != 3 {
+		t.Errorf("pinned, unpinned = %d, %d, want 1, 3", r.PinnedActions, r.UnpinnedActions)
+	}
+	scanning := securityCheck(t, r, "Code scanning workflow")
+	if !scanning.Passed || scanning.Detail != "github/codeql-action in .github/workflows/ci.yml" {
+		t.Errorf("scanning = %+v", scanning)
+	}
+	if pinning := securityCheck(t, r, "Actions pinned to commit SHAs"); pinning.Passed || pinning.Points != 2 {
+		t.Errorf("pinning = %+v, want 1 of 4 pinned for 2 points", pinning)
+	}
+}
+
+func securityCheck(t *testing.T, r SecurityReport, name string) SecurityCheck {
+	t.Helper()
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no %q check", name)
+	return SecurityCheck{}
+}
+
+func skippedChecks(r SecurityReport) []string {
+	var names []string
+	for _, c := range r.Checks {
+		if c.Skipped {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+func TestAnalyzeSecuritySkipped(t *testing.T) {
+	tree := []github.TreeEntry{
+		{Path: "SECURITY.md", Type: "blob"},
+		{Path: ".github/workflows/ci.yml", Type: "blob"},
+	}
+	signed := []github.Commit{{}}
+	signed[0].Commit.Verification.Verified = true
+	branch := &github.Branch{Name: "main", Protected: true}
+	workflows := map[string][]byte{".github/workflows/ci.yml": []byte("steps:\n  - run: make\n")}
+
+	tests := []struct {
+		name        string
+		tree        []github.TreeEntry
+		commits     []github.Commit
+		branch      *github.Branch
+		workflows   map[string][]byte
+		wantSkipped []string
+		wantScore   int
+	}{
+		{
+			name: "everything fetched", tree: tree, commits: signed, branch: branch, workflows: workflows,
+			// Only the dependency bot and code scanning checks fail: 35 of 100
+			wantScore: 65,
+		},
+		{
+			name: "no tree", commits: signed, branch: branch, workflows: workflows,
+			wantSkipped: []string{
+				"Security policy (SECURITY.md)", "Dependency updates (Dependabot/Renovate)", "Code scanning workflow",
+				"No secret-looking files committed", "Actions pinned to commit SHAs",
+			},
+			// Branch protection and signed commits, 30 of 30
+			wantScore: 100,
+		},
+		{
+			name: "workflows listed but not fetched", tree: tree, commits: signed, branch: branch,
+			wantSkipped: []string{"Code scanning workflow", "Actions pinned to commit SHAs"},
+			// 15 + 20 + 10 + 10 of 70
+			wantScore: 78,
+		},
+		{
+			name: "no branch", tree: tree, commits: signed, workflows: workflows,
+			wantSkipped: []string{"Default branch protected"},
+			// 15 + 10 + 10 + 10 of 80
+			wantScore: 56,
+		},
+		{
+			name: "commits not fetched", tree: tree, branch: branch, workflows: workflows,
+			wantSkipped: []string{"Signed commits"},
+			// 15 + 20 + 10 + 10 of 90
+			wantScore: 61,
+		},
+		{
+			name: "no commits in the window", tree: tree, commits: []github.Commit{}, branch: branch, workflows: workflows,
+			// Checked and failed: 55 of 100
+			wantScore: 55,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := AnalyzeSecurity(tt.tree, tt.commits, tt.branch, tt.workflows)
+			if got := skippedChecks(r); !reflect.DeepEqual(got, tt.wantSkipped) {
+				t.Errorf("skipped = %q, want %q", got, tt.wantSkipped)
+			}
+			if r.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d", r.Score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSecurityNothingFetched(t *testing.T) {
+	r := AnalyzeSecurity(nil, nil, nil, nil)
+	if len(skippedChecks(r)) != len(r.Checks) {
+		t.Errorf("checks run without data: %+v", r.Checks)
+	}
+	if r.Score != 0 || r.Grade != "-" || r.Verdict != "Unknown" {
+		t.Errorf("score, grade, verdict = %d, %q, %q", r.Score, r.Grade, r.Verdict)
+	}
+}
+
+func TestAnalyzeSecurityFindings(t *testing.T) {
+	tree := []github.TreeEntry{
+		{Path: ".env", Type: "blob"},
+		{Path: ".env.example", Type: "blob"},
+		{Path: "keys", Type: "tree"},
+		{Path: ".github/workflows/ci.yml", Type: "blob"},
+	}
+	workflows := map[string][]byte{".github/workflows/ci.yml": []byte("- uses: actions/checkout@v4\n")}
+	r := AnalyzeSecurity(tree, nil, nil, workflows)
+
+	if len(r.Findings) != 2 {
+		t.Fatalf("findings = %+v", r.Findings)
+	}
+	// High severity first, whatever order they were found in
+	if r.Findings[0].Severity != SeverityHigh || r.Findings[0].File != ".env" {
+		t.Errorf("first finding = %+v, want the .env file", r.Findings[0])
+	}
+	if r.Findings[1].Severity != SeverityLow {
+		t.Errorf("second finding = %+v, want the unpinned action", r.Findings[1])
+	}
+	if secrets := securityCheck(t, r, "No secret-looking files committed"); secrets.Passed || secrets.Detail != ".env" {
+		t.Errorf("secrets check = %+v", secrets)
+	}
+}
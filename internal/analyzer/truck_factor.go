@@ -0,0 +1,265 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// DefaultTruckThreshold is the share of files that must lose every author
+// before the project is considered stalled
+const DefaultTruckThreshold = 0.5
+
+// Truck factor modes
+const (
+	TruckModeAuthorship = "authorship"
+	TruckModeHeuristic  = "heuristic"
+)
+
+// KeyPerson is an author whose departure counts towards the truck factor
+type KeyPerson struct {
+	Login string
+	Files int     // files this person is an author of
+	Share float64 // Files as a share of all analyzed files
+}
+
+// DirectoryOwnership summarises who authors the files of a top-level directory
+type DirectoryOwnership struct {
+	Dir      string
+	Files    int
+	Owner    string  // author of the most files
+	Share    float64 // share of the directory's files Owner authors
+	Authors  int     // distinct authors in the directory
+	Orphaned bool    // every author of the directory is a key person
+}
+
+// TruckFactor is the number of people whose loss would leave more than
+// Threshold of the analyzed files without an author
+type TruckFactor struct {
+	Value          int
+	Risk           string
+	Mode           string
+	Threshold      float64
+	KeyPeople      []KeyPerson
+	Directories    []DirectoryOwnership
+	FilesAnalyzed  int
+	CommitsSampled int
+}
+
+// TruckFactorHeuristic wraps BusFactor, which only needs the contributor
+// list, for when commit file lists are unavailable
+func TruckFactorHeuristic(contributors []github.Contributor) TruckFactor {
+	value, risk := BusFactor(contributors)
+	tf := TruckFactor{Value: value, Risk: risk, Mode: TruckModeHeuristic}
+
+	total := 0
+	for _, c := range contributors {
+		total += c.Commits
+	}
+	for i := 0; i < value && i < len(contributors); i++ {
+		tf.KeyPeople = append(tf.KeyPeople, KeyPerson{
+			Login: contributors[i].Login,
+			Share: float64(contributors[i].Commits) / float64(total),
+		})
+	}
+	return tf
+}
+
+// fileHistory is what one author did to one file
+type fileHistory struct {
+	created bool
+	changes int
+}
+
+// CalculateTruckFactor computes the truck factor from degree-of-authorship
+// (Fritz et al.) over the files each sampled commit touched. commits must
+// include their file lists, as returned by GetCommit; only files still in
+// tree are counted. A threshold outside (0, 1) means DefaultTruckThreshold.
+func CalculateTruckFactor(commits []github.Commit, tree []github.TreeEntry, threshold float64) TruckFactor {
+	if threshold <= 0 || threshold >= 1 {
+		threshold = DefaultTruckThreshold
+	}
+	tf := TruckFactor{Mode: TruckModeAuthorship, Threshold: threshold, Risk: "Unknown"}
+
+	present := map[string]bool{}
+	for _, e := range tree {
+		if e.Type == "blob" {
+			present[e.Path] = true
+		}
+	}
+
+	// history[file][author]; oldest commit first so creations are seen in order
+	history := map[string]map[string]*fileHistory{}
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		if c.IsMerge() || len(c.Files) == 0 {
+			continue
+		}
+		tf.CommitsSampled++
+		author := c.AuthorLogin()
+
+		for _, f := range c.Files {
+			if len(present) > 0 && !present[f.Filename] {
+				continue
+			}
+			authors := history[f.Filename]
+			if authors == nil {
+				authors = map[string]*fileHistory{}
+				history[f.Filename] = authors
+			}
+			h := authors[author]
+			if h == nil {
+				h = &fileHistory{}
+				authors[author] = h
+			}
+			h.changes++
+			if f.Status == "added" {
+				h.created = true
+			}
+		}
+	}
+
+	// authorsOf[file] lists the people with a high enough degree of authorship
+	authorsOf := map[string][]string{}
+	for file, authors := range history {
+		total := 0
+		for _, h := range authors {
+			total += h.changes
+		}
+
+		doa := map[string]float64{}
+		best := 0.0
+		for author, h := range authors {
+			fa := 0.0
+			if h.created {
+				fa = 1
+			}
+			d := 3.293 + 1.098*fa + 0.164*float64(h.changes) - 0.321*math.Log(1+float64(total-h.changes))
+			doa[author] = d
+			best = math.Max(best, d)
+		}
+		for author, d := range doa {
+			if d/best > 0.75 && d >= 3.293 {
+				authorsOf[file] = append(authorsOf[file], author)
+			}
+		}
+		sort.Strings(authorsOf[file])
+	}
+	tf.FilesAnalyzed = len(authorsOf)
+	if tf.FilesAnalyzed == 0 {
+		return tf
+	}
+
+	fileCount := map[string]int{}
+	for _, authors := range authorsOf {
+		for _, a := range authors {
+			fileCount[a]++
+		}
+	}
+
+	// Greedily remove the author of the most files until enough are orphaned
+	removed := map[string]bool{}
+	for orphaned(authorsOf, removed) <= threshold*float64(tf.FilesAnalyzed) {
+		top, most := "", 0
+		for author, n := range fileCount {
+			if removed[author] {
+				continue
+			}
+			if n > most || (n == most && author < top) {
+				top, most = author, n
+			}
+		}
+		if top == "" {
+			break
+		}
+		removed[top] = true
+		tf.KeyPeople = append(tf.KeyPeople, KeyPerson{
+			Login: top,
+			Files: most,
+			Share: float64(most) / float64(tf.FilesAnalyzed),
+		})
+	}
+
+	tf.Value = len(tf.KeyPeople)
+	tf.Risk = truckRisk(tf.Value)
+	tf.Directories = directoryOwnership(authorsOf, removed)
+	return tf
+}
+
+// orphaned counts files whose every author has been removed
+func orphaned(authorsOf map[string][]string, removed map[string]bool) float64 {
+	n := 0
+	for _, authors := range authorsOf {
+		left := false
+		for _, a := range authors {
+			if !removed[a] {
+				left = true
+				break
+			}
+		}
+		if !left {
+			n++
+		}
+	}
+	return float64(n)
+}
+
+func truckRisk(value int) string {
+	switch {
+	case value <= 2:
+		return "High Risk"
+	case value <= 4:
+		return "Medium Risk"
+	default:
+		return "Low Risk"
+	}
+}
+
+// directoryOwnership groups authorship by top-level directory, largest first
+func directoryOwnership(authorsOf map[string][]string, keyPeople map[string]bool) []DirectoryOwnership {
+	type dirStats struct {
+		files   int
+		authors map[string]int
+	}
+	dirs := map[string]*dirStats{}
+	for file, authors := range authorsOf {
+		dir := "."
+		if i := strings.Index(file, "/"); i >= 0 {
+			dir = file[:i]
+		}
+		d := dirs[dir]
+		if d == nil {
+			d = &dirStats{authors: map[string]int{}}
+			dirs[dir] = d
+		}
+		d.files++
+		for _, a := range authors {
+			d.authors[a]++
+		}
+	}
+
+	var out []DirectoryOwnership
+	for name, d := range dirs {
+		own := DirectoryOwnership{Dir: name, Files: d.files, Authors: len(d.authors), Orphaned: true}
+		most := 0
+		for author, n := range d.authors {
+			if n > most || (n == most && author < own.Owner) {
+				own.Owner, most = author, n
+			}
+			if !keyPeople[author] {
+				own.Orphaned = false
+			}
+		}
+		own.Share = float64(most) / float64(d.files)
+		out = append(out, own)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Files != out[j].Files {
+			return out[i].Files > out[j].Files
+		}
+		return out[i].Dir < out[j].Dir
+	})
+	return out
+}
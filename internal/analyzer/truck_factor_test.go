@@ -0,0 +1,200 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// change is one author touching files in one commit; created files are
+// marked added
+type change struct {
+	author  string
+	created []string
+	edited  []string
+}
+
+// history builds commits newest first, as the API returns them, from
+// changes given oldest first
+func history(changes ...change) []github.Commit {
+	commits := make([]github.Commit, len(changes))
+	for i, ch := range changes {
+		var c github.Commit
+		c.Commit.Author.Name = ch.author
+		for _, f := range ch.created {
+			c.Files = append(c.Files, github.CommitFile{Filename: f, Status: "added"})
+		}
+		for _, f := range ch.edited {
+			c.Files = append(c.Files, github.CommitFile{Filename: f, Status: "modified"})
+		}
+		commits[len(changes)-1-i] = c
+	}
+	return commits
+}
+
+func repeat(ch change, n int) []change {
+	out := make([]change, n)
+	for i := range out {
+		out[i] = ch
+	}
+	return out
+}
+
+func keyLogins(tf TruckFactor) []string {
+	var logins []string
+	for _, p := range tf.KeyPeople {
+		logins = append(logins, p.Login)
+	}
+	return logins
+}
+
+func TestCalculateTruckFactor(t *testing.T) {
+	tests := []struct {
+		name      string
+		changes   []change
+		threshold float64
+		wantValue int
+		wantKey   []string
+	}{
+		{
+			name:      "single author",
+			changes:   []change{{author: "alice", created: []string{"a", "b", "c", "d"}}},
+			wantValue: 1,
+			wantKey:   []string{"alice"},
+		},
+		{
+			name: "two owners of half each",
+			// Removing alice orphans exactly half, which is not more than
+			// the threshold, so bob has to go too
+			changes: []change{
+				{author: "alice", created: []string{"a/1", "a/2"}},
+				{author: "bob", created: []string{"b/1", "b/2"}},
+			},
+			wantValue: 2,
+			wantKey:   []string{"alice", "bob"},
+		},
+		{
+			name: "three owners, largest first",
+			changes: []change{
+				{author: "carol", created: []string{"c/1"}},
+				{author: "alice", created: []string{"a/1", "a/2", "a/3"}},
+				{author: "bob", created: []string{"b/1", "b/2"}},
+			},
+			wantValue: 2,
+			wantKey:   []string{"alice", "bob"},
+		},
+		{
+			name:      "lower threshold needs fewer people",
+			changes:   []change{{author: "alice", created: []string{"a/1", "a/2"}}, {author: "bob", created: []string{"b/1", "b/2"}}},
+			threshold: 0.25,
+			wantValue: 1,
+			wantKey:   []string{"alice"},
+		},
+		{
+			name: "heavy editor shares authorship with the creator",
+			changes: append(
+				[]change{{author: "alice", created: []string{"f1", "f2"}}},
+				repeat(change{author: "bob", edited: []string{"f1", "f2"}}, 10)...,
+			),
+			wantValue: 2,
+			wantKey:   []string{"alice", "bob"},
+		},
+		{
+			name: "drive-by edit does not make an author",
+			changes: append(
+				append([]change{{author: "alice", created: []string{"f1", "f2"}}},
+					repeat(change{author: "alice", edited: []string{"f1", "f2"}}, 5)...),
+				change{author: "bob", edited: []string{"f1"}},
+			),
+			wantValue: 1,
+			wantKey:   []string{"alice"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf := CalculateTruckFactor(history(tt.changes...), nil, tt.threshold)
+			if tf.Value != tt.wantValue {
+				t.Errorf("Value = %d, want %d (key people %v)", tf.Value, tt.wantValue, keyLogins(tf))
+			}
+			got := keyLogins(tf)
+			if len(got) != len(tt.wantKey) {
+				t.Fatalf("key people = %v, want %v", got, tt.wantKey)
+			}
+			for i := range got {
+				if got[i] != tt.wantKey[i] {
+					t.Errorf("key people = %v, want %v", got, tt.wantKey)
+					break
+				}
+			}
+			if tf.Mode != TruckModeAuthorship {
+				t.Errorf("Mode = %q", tf.Mode)
+			}
+		})
+	}
+}
+
+func TestCalculateTruckFactorFiltering(t *testing.T) {
+	commits := history(
+		change{author: "alice", created: []string{"kept.go", "deleted.go"}},
+		change{author: "bob", created: []string{"merged.go"}},
+	)
+	commits[0].Parents = make([]struct {
+		SHA string `json:"sha"`
+	}, 2) // bob's commit is a merge
+	tree := []github.TreeEntry{
+		{Path: "kept.go", Type: "blob"},
+		{Path: "merged.go", Type: "blob"},
+		{Path: "dir", Type: "tree"},
+	}
+
+	tf := CalculateTruckFactor(commits, tree, 0)
+	if tf.FilesAnalyzed != 1 || tf.CommitsSampled != 1 {
+		t.Errorf("FilesAnalyzed, CommitsSampled = %d, %d, want 1, 1", tf.FilesAnalyzed, tf.CommitsSampled)
+	}
+	if tf.Threshold != DefaultTruckThreshold {
+		t.Errorf("Threshold = %v, want the default", tf.Threshold)
+	}
+	if tf.Value != 1 || tf.KeyPeople[0].Share != 1 {
+		t.Errorf("Value = %d, key people %+v", tf.Value, tf.KeyPeople)
+	}
+}
+
+func TestCalculateTruckFactorNoData(t *testing.T) {
+	tf := CalculateTruckFactor(nil, nil, 0.5)
+	if tf.Value != 0 || tf.Risk != "Unknown" || tf.FilesAnalyzed != 0 {
+		t.Errorf("empty history = %+v", tf)
+	}
+}
+
+func TestCalculateTruckFactorDirectories(t *testing.T) {
+	tf := CalculateTruckFactor(history(
+		change{author: "alice", created: []string{"a/1", "a/2", "a/3"}},
+		change{author: "bob", created: []string{"b/1", "README"}},
+		change{author: "carol", created: []string{"b/2"}},
+	), nil, 0.5)
+
+	want := map[string]DirectoryOwnership{
+		"a": {Dir: "a", Files: 3, Owner: "alice", Share: 1, Authors: 1, Orphaned: true},
+		"b": {Dir: "b", Files: 2, Owner: "bob", Share: 0.5, Authors: 2, Orphaned: false},
+		".": {Dir: ".", Files: 1, Owner: "bob", Share: 1, Authors: 1, Orphaned: true},
+	}
+	if len(tf.Directories) != len(want) {
+		t.Fatalf("directories = %+v", tf.Directories)
+	}
+	if tf.Directories[0].Dir != "a" {
+		t.Errorf("largest directory first, got %q", tf.Directories[0].Dir)
+	}
+	for _, d := range tf.Directories {
+		if d != want[d.Dir] {
+			t.Errorf("directory %q = %+v, want %+v", d.Dir, d, want[d.Dir])
+		}
+	}
+}
+
+func TestTruckRisk(t *testing.T) {
+	for value, want := range map[int]string{1: "High Risk", 2: "High Risk", 3: "Medium Risk", 4: "Medium Risk", 5: "Low Risk"} {
+		if got := truckRisk(value); got != want {
+			t.Errorf("truckRisk(%d) = %q, want %q", value, got, want)
+		}
+	}
+}
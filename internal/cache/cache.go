@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is one cached HTTP response.
+type Entry struct {
+	URL          string      `json:"url"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	StoredAt     time.Time   `json:"stored_at"`
+	ExpiresAt    time.Time   `json:"expires_at"` // served without revalidation until then
+}
+
+// Fresh reports whether the entry can be used without asking the server.
+func (e *Entry) Fresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// Stats summarises the contents of a Store.
+type Stats struct {
+	Dir     string
+	Entries int
+	Bytes   int64
+	Fresh   int
+	Stale   int
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// Store is a directory of cached responses, one JSON file per key.
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the cache location under the user cache directory.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "repolyzer", "http"), nil
+}
+
+// Open returns a Store rooted at dir, creating it if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the directory backing the store.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Key derives a cache key from a request URL and the identity of the
+// credentials used, so different tokens never share private responses.
+// The token itself is hashed and never written to disk.
+func Key(url, token string) string {
+	identity := ""
+	if token != "" {
+		sum := sha256.Sum256([]byte(token))
+		identity = hex.EncodeToString(sum[:])
+	}
+	sum := sha256.Sum256([]byte(identity + " " + url))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get loads the entry stored under key.
+func (s *Store) Get(key string) (*Entry, bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+// Put stores e under key, replacing any previous entry.
+func (s *Store) Put(key string, e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Stats walks the store and reports its size and freshness.
+func (s *Store) Stats() (Stats, error) {
+	stats := Stats{Dir: s.dir}
+	now := time.Now()
+
+	err := s.each(func(path string, info os.FileInfo) error {
+		stats.Entries++
+		stats.Bytes += info.Size()
+
+		e, ok := s.Get(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if !ok {
+			stats.Stale++
+			return nil
+		}
+		if e.Fresh(now) {
+			stats.Fresh++
+		} else {
+			stats.Stale++
+		}
+		if stats.Oldest.IsZero() || e.StoredAt.Before(stats.Oldest) {
+			stats.Oldest = e.StoredAt
+		}
+		if e.StoredAt.After(stats.Newest) {
+			stats.Newest = e.StoredAt
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Clear removes every entry and returns how many were deleted.
+func (s *Store) Clear() (int, error) {
+	removed := 0
+	err := s.each(func(path string, _ os.FileInfo) error {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// Prune removes entries that expired more than olderThan ago, along with
+// unreadable ones. Expired entries younger than that are kept because their
+// ETag still saves bandwidth on revalidation.
+func (s *Store) Prune(olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+
+	err := s.each(func(path string, _ os.FileInfo) error {
+		e, ok := s.Get(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if ok && e.ExpiresAt.After(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+// each calls fn for every entry file in the store.
+func (s *Store) each(fn func(path string, info os.FileInfo) error) error {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, de := range entries {
+		if de.IsDir() || filepath.Ext(de.Name()) != ".json" {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		if err := fn(filepath.Join(s.dir, de.Name()), info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Response rebuilds an *http.Response for req from the entry. The
+// X-From-Cache header marks it as served locally.
+func (e *Entry) Response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("X-From-Cache", "1")
+
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTemp(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestKey(t *testing.T) {
+	if Key("u", "") != Key("u", "") {
+		t.Error("Key is not deterministic")
+	}
+	if Key("u", "a") == Key("u", "b") {
+		t.Error("different tokens share a key")
+	}
+	if Key("u", "") == Key("u", "a") {
+		t.Error("anonymous and authenticated requests share a key")
+	}
+	if Key("u1", "a") == Key("u2", "a") {
+		t.Error("different URLs share a key")
+	}
+}
+
+func TestPutGet(t *testing.T) {
+	s := openTemp(t)
+	if _, ok := s.Get("missing"); ok {
+		t.Fatal("Get on an empty store succeeded")
+	}
+
+	now := time.Now().Truncate(time.Second)
+	want := &Entry{
+		URL:        "https://api.github.com/repos/o/r",
+		StatusCode: 200,
+		Header:     http.Header{"Etag": {`"abc"`}},
+		Body:       []byte(`{"x":1}`),
+		ETag:       `"abc"`,
+		StoredAt:   now,
+		ExpiresAt:  now.Add(time.Minute),
+	}
+	if err := s.Put("k", want); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := s.Get("k")
+	if !ok {
+		t.Fatal("Get after Put failed")
+	}
+	if got.URL != want.URL || string(got.Body) != string(want.Body) || got.ETag != want.ETag || !got.ExpiresAt.Equal(want.ExpiresAt) {
+		t.Errorf("Get = %+v, want %+v", got, want)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(s.Dir(), "*.tmp"))
+	if len(matches) != 0 {
+		t.Errorf("temp files left behind: %v", matches)
+	}
+}
+
+func TestGetCorrupt(t *testing.T) {
+	s := openTemp(t)
+	os.WriteFile(s.path("bad"), []byte("{not json"), 0o600)
+	if _, ok := s.Get("bad"); ok {
+		t.Error("Get returned a corrupt entry")
+	}
+}
+
+func TestFresh(t *testing.T) {
+	now := time.Now()
+	e := &Entry{ExpiresAt: now.Add(time.Second)}
+	if !e.Fresh(now) {
+		t.Error("entry before its expiry is not fresh")
+	}
+	if e.Fresh(now.Add(time.Second)) {
+		t.Error("entry at its expiry is still fresh")
+	}
+	if e.Fresh(now.Add(time.Hour)) {
+		t.Error("expired entry is fresh")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := openTemp(t)
+	now := time.Now()
+	s.Put("fresh", &Entry{ExpiresAt: now.Add(time.Hour)})
+	s.Put("recently-expired", &Entry{ExpiresAt: now.Add(-time.Hour)})
+	s.Put("long-expired", &Entry{ExpiresAt: now.Add(-48 * time.Hour)})
+	os.WriteFile(s.path("corrupt"), []byte("garbage"), 0o600)
+	os.WriteFile(filepath.Join(s.Dir(), "notes.txt"), []byte("not an entry"), 0o600)
+
+	removed, err := s.Prune(24 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("Prune removed %d entries, want 2", removed)
+	}
+	for key, want := range map[string]bool{"fresh": true, "recently-expired": true, "long-expired": false, "corrupt": false} {
+		if _, err := os.Stat(s.path(key)); (err == nil) != want {
+			t.Errorf("%s kept = %v, want %v", key, err == nil, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir(), "notes.txt")); err != nil {
+		t.Error("Prune removed a file that is not an entry")
+	}
+}
+
+func TestStatsAndClear(t *testing.T) {
+	s := openTemp(t)
+	now := time.Now()
+	s.Put("a", &Entry{Body: []byte("a"), StoredAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)})
+	s.Put("b", &Entry{Body: []byte("b"), StoredAt: now, ExpiresAt: now.Add(-time.Minute)})
+
+	stats, err := s.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 2 || stats.Fresh != 1 || stats.Stale != 1 {
+		t.Errorf("Stats = %+v, want 2 entries, 1 fresh, 1 stale", stats)
+	}
+	if !stats.Oldest.Equal(now.Add(-time.Hour)) || !stats.Newest.Equal(now) {
+		t.Errorf("Oldest, Newest = %v, %v", stats.Oldest, stats.Newest)
+	}
+
+	removed, err := s.Clear()
+	if err != nil || removed != 2 {
+		t.Errorf("Clear = %d, %v, want 2, nil", removed, err)
+	}
+	if stats, _ := s.Stats(); stats.Entries != 0 {
+		t.Errorf("%d entries left after Clear", stats.Entries)
+	}
+}
+
+func TestEntryResponse(t *testing.T) {
+	e := &Entry{StatusCode: 200, Header: http.Header{"Content-Type": {"application/json"}}, Body: []byte("{}")}
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	resp := e.Response(req)
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || string(body) != "{}" || resp.Request != req {
+		t.Errorf("Response = %d %q", resp.StatusCode, body)
+	}
+	if resp.Header.Get("X-From-Cache") == "" {
+		t.Error("cached response is not marked X-From-Cache")
+	}
+	if e.Header.Get("X-From-Cache") != "" {
+		t.Error("Response modified the stored header")
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/cache"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// Config holds user settings read from the config file and environment.
+type Config struct {
+	// APIURL is the REST endpoint, e.g. "https://ghe.example.com/api/v3/"
+	// for GitHub Enterprise Server. Empty means github.com.
+	APIURL string `json:"api_url,omitempty"`
+	// UploadURL is the upload endpoint. Empty derives it from APIURL.
+	UploadURL string `json:"upload_url,omitempty"`
+	// UserAgent overrides the User-Agent header sent to the API.
+	UserAgent string `json:"user_agent,omitempty"`
+	// NoCache disables the on-disk HTTP cache.
+	NoCache bool `json:"no_cache,omitempty"`
+	// CacheDir overrides the cache location (default: user cache dir).
+	CacheDir string `json:"cache_dir,omitempty"`
+	// CompareWeights sets how much each dimension counts towards the
+	// overall compare ranking, e.g. {"health": 2, "prs": 0}. Dimensions
+	// left out weigh 1.
+	CompareWeights map[string]float64 `json:"compare_weights,omitempty"`
+	// HealthProfile is a built-in health profile name or the path of a
+	// YAML or JSON profile. Empty means the default preset.
+	HealthProfile string `json:"health_profile,omitempty"`
+}
+
+// Path returns the location of the config file
+// ($XDG_CONFIG_HOME/repolyzer/config.json or the platform equivalent).
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "repolyzer", "config.json"), nil
+}
+
+// Load reads the config file, if present, and applies environment
+// overrides (REPOLYZER_API_URL, REPOLYZER_UPLOAD_URL, REPOLYZER_NO_CACHE,
+// REPOLYZER_CACHE_DIR).
+func Load() (Config, error) {
+	var cfg Config
+
+	if path, err := Path(); err == nil {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return cfg, err
+		}
+	}
+
+	if v := os.Getenv("REPOLYZER_API_URL"); v != "" {
+		cfg.APIURL = v
+	}
+	if v := os.Getenv("REPOLYZER_UPLOAD_URL"); v != "" {
+		cfg.UploadURL = v
+	}
+	if v := os.Getenv("REPOLYZER_NO_CACHE"); v != "" && v != "0" {
+		cfg.NoCache = true
+	}
+	if v := os.Getenv("REPOLYZER_CACHE_DIR"); v != "" {
+		cfg.CacheDir = v
+	}
+
+	return cfg, nil
+}
+
+// OpenCache opens the HTTP cache store described by the config.
+func (c Config) OpenCache() (*cache.Store, error) {
+	dir := c.CacheDir
+	if dir == "" {
+		var err error
+		if dir, err = cache.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return cache.Open(dir)
+}
+
+// ClientOptions translates the config into github.Client options. A cache
+// that cannot be opened is skipped rather than failing the run.
+func (c Config) ClientOptions() []github.Option {
+	var opts []github.Option
+	if !c.NoCache {
+		if store, err := c.OpenCache(); err == nil {
+			opts = append(opts, github.WithCache(store))
+		}
+	}
+	if c.APIURL != "" {
+		opts = append(opts, github.WithBaseURL(c.APIURL))
+	}
+	if c.UploadURL != "" {
+		opts = append(opts, github.WithUploadURL(c.UploadURL))
+	}
+	if c.UserAgent != "" {
+		opts = append(opts, github.WithUserAgent(c.UserAgent))
+	}
+	return opts
+}
+
+// LoadHealthProfile resolves spec, a built-in profile name or the path of a
+// YAML or JSON profile file. An empty spec is the default preset.
+func LoadHealthProfile(spec string) (analyzer.HealthProfile, error) {
+	if spec == "" {
+		spec = analyzer.DefaultHealthProfile
+	}
+	if p, ok := analyzer.HealthPreset(spec); ok {
+		return p, nil
+	}
+	data, err := os.ReadFile(spec)
+	if errors.Is(err, os.ErrNotExist) {
+		return analyzer.HealthProfile{}, fmt.Errorf("health profile %q is neither a preset (%s) nor a file", spec, strings.Join(analyzer.HealthPresetNames(), ", "))
+	}
+	if err != nil {
+		return analyzer.HealthProfile{}, err
+	}
+	p, err := analyzer.ParseHealthProfile(data)
+	if err != nil {
+		return p, fmt.Errorf("%s: %w", spec, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(spec), filepath.Ext(spec))
+	}
+	return p, nil
+}
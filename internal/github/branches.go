@@ -0,0 +1,22 @@
+package github
+
+import "context"
+
+// Branch is a repository branch. Protected is visible without admin rights,
+// unlike the protection rules themselves.
+type Branch struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+	Commit    struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+// GetBranch fetches a single branch.
+func (c *Client) GetBranch(ctx context.Context, owner, repo, branch string) (*Branch, error) {
+	var b Branch
+	if err := c.get(ctx, c.endpoint("repos/%s/%s/branches/", owner, repo)+escapePath(branch), &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
@@ -0,0 +1,108 @@
+package github
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/cache"
+)
+
+// WithCache serves GET requests from store when fresh and revalidates stale
+// entries with If-None-Match / If-Modified-Since. GitHub does not charge
+// 304 Not Modified responses against the rate limit.
+func WithCache(store *cache.Store) Option {
+	return func(c *Client) {
+		c.cache = store
+	}
+}
+
+var shaRef = regexp.MustCompile(`/(git/trees|git/blobs|commits)/[0-9a-f]{40}(\?|$)`)
+
+// cacheTTL decides how long a response for path may be served without
+// revalidation. Zero disables caching for that endpoint.
+func cacheTTL(path string) time.Duration {
+	switch {
+	case strings.HasSuffix(path, "/rate_limit"):
+		return 0
+	case shaRef.MatchString(path):
+		// Addressed by content hash, so it can never change
+		return 30 * 24 * time.Hour
+	case strings.Contains(path, "/commits"):
+		return 10 * time.Minute
+	case strings.Contains(path, "/git/trees/"):
+		return time.Hour
+	case strings.HasSuffix(path, "/contributors"), strings.HasSuffix(path, "/languages"):
+		return 6 * time.Hour
+	default:
+		return 5 * time.Minute
+	}
+}
+
+// cacheTransport sits in front of the rate-limit transport so that fresh
+// hits never touch the network or the budget.
+type cacheTransport struct {
+	next  http.RoundTripper
+	store *cache.Store
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ttl := cacheTTL(req.URL.Path)
+	if req.Method != http.MethodGet || ttl == 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	key := cache.Key(req.URL.String(), strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+	entry, cached := t.store.Get(key)
+	if cached && entry.Fresh(time.Now()) {
+		return entry.Response(req), nil
+	}
+
+	if cached {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		entry.ExpiresAt = entry.StoredAt.Add(ttl)
+		t.store.Put(key, entry)
+		return entry.Response(req), nil
+
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		t.store.Put(key, &cache.Entry{
+			URL:          req.URL.String(),
+			StatusCode:   resp.StatusCode,
+			Header:       resp.Header.Clone(),
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			StoredAt:     now,
+			ExpiresAt:    now.Add(ttl),
+		})
+	}
+	return resp, nil
+}
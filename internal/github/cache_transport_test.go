@@ -0,0 +1,211 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/cache"
+)
+
+func TestCacheTTL(t *testing.T) {
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	tests := []struct {
+		path string
+		want time.Duration
+	}{
+		{"/rate_limit", 0},
+		{"/repos/o/r/git/trees/" + sha, 30 * 24 * time.Hour},
+		{"/repos/o/r/git/blobs/" + sha, 30 * 24 * time.Hour},
+		{"/repos/o/r/commits/" + sha, 30 * 24 * time.Hour},
+		{"/repos/o/r/commits", 10 * time.Minute},
+		{"/repos/o/r/commits/main", 10 * time.Minute},
+		{"/repos/o/r/git/trees/main", time.Hour},
+		{"/repos/o/r/contributors", 6 * time.Hour},
+		{"/repos/o/r/languages", 6 * time.Hour},
+		{"/repos/o/r", 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := cacheTTL(tt.path); got != tt.want {
+			t.Errorf("cacheTTL(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// revalidatingServer answers with an ETag and Last-Modified, and 304 when
+// the request carries either validator
+type revalidatingServer struct {
+	requests    int32
+	notModified int32
+	lastHeader  http.Header
+}
+
+func (s *revalidatingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.requests, 1)
+	s.lastHeader = r.Header.Clone()
+	if r.Header.Get("If-None-Match") == `"v1"` || r.Header.Get("If-Modified-Since") == "Mon, 01 Jan 2024 00:00:00 GMT" {
+		atomic.AddInt32(&s.notModified, 1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", `"v1"`)
+	w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+	io.WriteString(w, `{"full_name":"o/r","stargazers_count":7}`)
+}
+
+func newCachedClient(t *testing.T, handler http.Handler) (*Client, *cache.Store, string) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	store, err := cache.Open(filepath.Join(t.TempDir(), "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(WithBaseURL(srv.URL), WithToken(""), WithRetryPolicy(RetryPolicy{}), WithCache(store))
+	return c, store, srv.URL + "/repos/o/r"
+}
+
+// expire marks the stored entry for url stale
+func expire(t *testing.T, store *cache.Store, url string) *cache.Entry {
+	t.Helper()
+	key := cache.Key(url, "")
+	e, ok := store.Get(key)
+	if !ok {
+		t.Fatalf("%s was not cached", url)
+	}
+	e.ExpiresAt = time.Now().Add(-time.Second)
+	store.Put(key, e)
+	return e
+}
+
+func TestCacheFreshHitSkipsNetwork(t *testing.T) {
+	srv := &revalidatingServer{}
+	c, _, _ := newCachedClient(t, srv)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		repo, err := c.GetRepo(ctx, "o", "r")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repo.Stars != 7 {
+			t.Fatalf("Stars = %d, want 7", repo.Stars)
+		}
+	}
+	if srv.requests != 1 {
+		t.Errorf("made %d requests, want 1", srv.requests)
+	}
+}
+
+func TestCacheRevalidatesStaleEntry(t *testing.T) {
+	srv := &revalidatingServer{}
+	c, store, url := newCachedClient(t, srv)
+	ctx := context.Background()
+
+	if _, err := c.GetRepo(ctx, "o", "r"); err != nil {
+		t.Fatal(err)
+	}
+	expire(t, store, url)
+
+	repo, err := c.GetRepo(ctx, "o", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Stars != 7 {
+		t.Errorf("Stars after 304 = %d, want the cached 7", repo.Stars)
+	}
+	if srv.requests != 2 || srv.notModified != 1 {
+		t.Errorf("requests, 304s = %d, %d, want 2, 1", srv.requests, srv.notModified)
+	}
+	if got := srv.lastHeader.Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("If-None-Match = %q", got)
+	}
+	if got := srv.lastHeader.Get("If-Modified-Since"); got != "Mon, 01 Jan 2024 00:00:00 GMT" {
+		t.Errorf("If-Modified-Since = %q", got)
+	}
+
+	// The 304 renewed the entry, so the next call is a fresh hit
+	e, _ := store.Get(cache.Key(url, ""))
+	if !e.Fresh(time.Now()) {
+		t.Error("entry is still stale after a 304")
+	}
+	c.GetRepo(ctx, "o", "r")
+	if srv.requests != 2 {
+		t.Errorf("made %d requests after renewal, want 2", srv.requests)
+	}
+}
+
+func TestCacheRevalidatesWithLastModifiedOnly(t *testing.T) {
+	srv := &revalidatingServer{}
+	c, store, url := newCachedClient(t, srv)
+	ctx := context.Background()
+
+	c.GetRepo(ctx, "o", "r")
+	e := expire(t, store, url)
+	e.ETag = ""
+	store.Put(cache.Key(url, ""), e)
+
+	if _, err := c.GetRepo(ctx, "o", "r"); err != nil {
+		t.Fatal(err)
+	}
+	if srv.lastHeader.Get("If-None-Match") != "" {
+		t.Error("sent If-None-Match without a stored ETag")
+	}
+	if srv.notModified != 1 {
+		t.Errorf("got %d 304s, want 1", srv.notModified)
+	}
+}
+
+func TestCacheReplacesChangedEntry(t *testing.T) {
+	var version int32 = 1
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := atomic.LoadInt32(&version)
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, v))
+		fmt.Fprintf(w, `{"stargazers_count":%d}`, v)
+	})
+	c, store, url := newCachedClient(t, handler)
+	ctx := context.Background()
+
+	c.GetRepo(ctx, "o", "r")
+	expire(t, store, url)
+	atomic.StoreInt32(&version, 2)
+
+	repo, err := c.GetRepo(ctx, "o", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Stars != 2 {
+		t.Errorf("Stars = %d, want the new 2", repo.Stars)
+	}
+	if e, _ := store.Get(cache.Key(url, "")); e.ETag != `"v2"` {
+		t.Errorf("stored ETag = %q, want the new one", e.ETag)
+	}
+}
+
+func TestCacheSkipsErrorsAndRateLimit(t *testing.T) {
+	var requests int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/repos/o/r" {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, `{}`)
+	})
+	c, _, _ := newCachedClient(t, handler)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		c.GetRepo(ctx, "o", "r")
+		c.GetRateLimit(ctx)
+	}
+	if requests != 4 {
+		t.Errorf("made %d requests, want every request to reach the server", requests)
+	}
+}
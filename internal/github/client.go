@@ -1,48 +1,155 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
+	"net/url"
+
+	"github.com/agnivo988/Repo-lyzer/internal/cache"
 )
 
 type Client struct {
-	http *http.Client
-	token string
+	http      *http.Client
+	tokens    TokenSource
+	baseURL   *url.URL
+	uploadURL *url.URL
+	uploadSet bool
+	userAgent string
+	configErr error
+	retry     RetryPolicy
+	limiter   *rateLimitTransport
+	cache     *cache.Store
 }
 
-func NewClient() *Client {
-	return &Client{
-		http:  &http.Client{},
-		token: os.Getenv("GITHUB_TOKEN"),
+// NewClient creates a client for github.com authenticated with the
+// GITHUB_TOKEN environment variable; opts override any of those defaults.
+func NewClient(opts ...Option) *Client {
+	base, _ := url.Parse(DefaultBaseURL)
+	upload, _ := url.Parse(DefaultUploadURL)
+
+	c := &Client{
+		http:      &http.Client{},
+		tokens:    EnvToken("GITHUB_TOKEN"),
+		baseURL:   base,
+		uploadURL: upload,
+		userAgent: DefaultUserAgent,
+		retry:     DefaultRetryPolicy,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// Wrap a copy so a caller-supplied *http.Client is left untouched
+	hc := *c.http
+	c.limiter = newRateLimitTransport(hc.Transport, c.retry)
+	hc.Transport = c.limiter
+	if c.cache != nil {
+		hc.Transport = &cacheTransport{next: c.limiter, store: c.cache}
+	}
+	c.http = &hc
+
+	return c
+}
+
+// RateBudget returns the core rate budget reported by the most recent API
+// response, without spending a request on /rate_limit. ok is false until
+// the first response has been seen.
+func (c *Client) RateBudget() (budget *RateLimit, ok bool) {
+	b, ok := c.limiter.Budget()
+	return &b, ok
+}
+
+// BaseURL returns the REST endpoint the client talks to.
+func (c *Client) BaseURL() string {
+	return c.baseURL.String()
 }
 
-func (c *Client) get(url string, target interface{}) error {
-	req, err := http.NewRequest("GET", url, nil)
+// UploadURL returns the upload endpoint the client talks to.
+func (c *Client) UploadURL() string {
+	return c.uploadURL.String()
+}
+
+// Authenticated reports whether requests carry a token.
+func (c *Client) Authenticated() bool {
+	if c.tokens == nil {
+		return false
+	}
+	token, err := c.tokens.Token()
+	return err == nil && token != ""
+}
+
+// endpoint resolves an API path against the base URL. Each arg is
+// path-escaped before being substituted into format, so refs that may
+// contain slashes (branch names) are appended with escapePath instead.
+func (c *Client) endpoint(format string, args ...interface{}) string {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		if s, ok := arg.(string); ok {
+			escaped[i] = url.PathEscape(s)
+		} else {
+			escaped[i] = arg
+		}
+	}
+	return c.baseURL.String() + fmt.Sprintf(format, escaped...)
+}
+
+func (c *Client) get(ctx context.Context, url string, target interface{}) error {
+	_, err := c.getPage(ctx, url, target)
+	return err
+}
+
+// getPage fetches url into target and returns the URL of the next page
+// advertised in the Link header, or "" when this was the last page.
+func (c *Client) getPage(ctx context.Context, url string, target interface{}) (string, error) {
+	if c.configErr != nil {
+		return "", c.configErr
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", c.userAgent)
 
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	if c.tokens != nil {
+		token, err := c.tokens.Token()
+		if err != nil {
+			return "", fmt.Errorf("obtaining GitHub token: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
 	}
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf(
-	    "GitHub API error: %s (tip: set GITHUB_TOKEN env variable)",
-	       resp.Status,
-)
+	// 204 No Content is how GitHub lists nothing, e.g. contributors of an empty repo
+	if resp.StatusCode == http.StatusNoContent {
+		return "", nil
+	}
+	if err := checkResponse(resp); err != nil {
+		return "", err
 	}
 
-	return json.NewDecoder(resp.Body).Decode(target)
+	body := &countingReader{r: resp.Body}
+	if err := json.NewDecoder(body).Decode(target); err != nil {
+		return "", err
+	}
+
+	if observe := pageObserver(ctx); observe != nil {
+		observe(PageInfo{
+			URL:       url,
+			Bytes:     body.n,
+			FromCache: resp.Header.Get("X-From-Cache") != "",
+		})
+	}
+	return nextPageURL(resp.Header.Get("Link")), nil
 }
@@ -0,0 +1,39 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestRefPathsKeepSlashes(t *testing.T) {
+	var paths []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		json.NewEncoder(w).Encode(map[string]any{})
+	}))
+	ctx := context.Background()
+
+	c.GetFileTree(ctx, "o", "r", "feature/x")
+	c.GetTree(ctx, "o", "r", "release/1.0")
+	c.GetBranch(ctx, "o", "r", "feature/x")
+	c.GetCommit(ctx, "o", "r", "fix/a b")
+	c.GetRepo(ctx, "o w", "r")
+
+	want := []string{
+		"/repos/o/r/git/trees/feature/x",
+		"/repos/o/r/git/trees/release/1.0",
+		"/repos/o/r/branches/feature/x",
+		"/repos/o/r/commits/fix/a%20b",
+		"/repos/o%20w/r",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("got %d requests, want %d: %v", len(paths), len(want), paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("request %d path = %q, want %q", i, paths[i], want[i])
+		}
+	}
+}
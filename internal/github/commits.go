@@ -1,22 +1,173 @@
 package github
 
-import "time"
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
 
 type Commit struct {
 	SHA    string `json:"sha"`
 	Commit struct {
 		Author struct {
-			Date time.Time `json:"date"`
+			Name  string    `json:"name"`
+			Email string    `json:"email"`
+			Date  time.Time `json:"date"`
 		} `json:"author"`
+		Message      string `json:"message"`
+		Verification struct {
+			Verified bool   `json:"verified"`
+			Reason   string `json:"reason"`
+		} `json:"verification"`
 	} `json:"commit"`
+	Author  *User `json:"author"` // linked GitHub account; nil when the email is unknown
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Files []CommitFile `json:"files"` // only returned by GetCommit
 }
 
+// CommitFile is one file changed by a commit.
+type CommitFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+	Status           string `json:"status"` // added, modified, removed, renamed, ...
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+}
+
+// AuthorLogin identifies the author by GitHub login, falling back to the
+// git author name for commits not linked to an account.
+func (c Commit) AuthorLogin() string {
+	if c.Author != nil && c.Author.Login != "" {
+		return c.Author.Login
+	}
+	return c.Commit.Author.Name
+}
+
+// IsMerge reports whether the commit has more than one parent.
+func (c Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// CommitOptions narrows a commit listing. Zero values mean "no filter".
+type CommitOptions struct {
+	Since    time.Time // only commits after this time
+	Until    time.Time // only commits before this time
+	SHA      string    // branch name or commit SHA to start listing from
+	Path     string    // only commits touching this file or directory
+	Author   string    // GitHub login or email address
+	MaxCount int       // stop after this many commits; 0 means no cap
+}
+
+func (o CommitOptions) query() url.Values {
+	q := url.Values{}
+	q.Set("per_page", strconv.Itoa(perPage))
+	if !o.Since.IsZero() {
+		q.Set("since", o.Since.UTC().Format(time.RFC3339))
+	}
+	if !o.Until.IsZero() {
+		q.Set("until", o.Until.UTC().Format(time.RFC3339))
+	}
+	if o.SHA != "" {
+		q.Set("sha", o.SHA)
+	}
+	if o.Path != "" {
+		q.Set("path", o.Path)
+	}
+	if o.Author != "" {
+		q.Set("author", o.Author)
+	}
+	return q
+}
+
+// CommitIterator walks a commit listing page by page, following the Link
+// headers returned by the API. Use it like bufio.Scanner:
+//
+//	it := client.IterCommits(ctx, owner, repo, opts)
+//	for it.Next() {
+//		c := it.Commit()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type CommitIterator struct {
+	ctx     context.Context
+	client  *Client
+	nextURL string
+	buf     []Commit
+	current Commit
+	count   int
+	max     int
+	err     error
+}
+
+// IterCommits returns an iterator over the commits of owner/repo matching opts.
+// No request is made until the first call to Next; ctx bounds every page
+// fetched afterwards.
+func (c *Client) IterCommits(ctx context.Context, owner, repo string, opts CommitOptions) *CommitIterator {
+	return &CommitIterator{
+		ctx:     ctx,
+		client:  c,
+		nextURL: c.endpoint("repos/%s/%s/commits", owner, repo) + "?" + opts.query().Encode(),
+		max:     opts.MaxCount,
+	}
+}
+
+// Next advances to the next commit, fetching another page when needed.
+// It returns false when the listing is exhausted, the cap is reached or an
+// error occurred.
+func (it *CommitIterator) Next() bool {
+	if it.err != nil || (it.max > 0 && it.count >= it.max) {
+		return false
+	}
+
+	for len(it.buf) == 0 {
+		if it.nextURL == "" {
+			return false
+		}
+
+		var page []Commit
+		next, err := it.client.getPage(it.ctx, it.nextURL, &page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.buf = page
+		it.nextURL = next
+	}
+
+	it.current = it.buf[0]
+	it.buf = it.buf[1:]
+	it.count++
+	return true
+}
 
-func (c *Client) GetCommits(owner, repo string, days int) ([]Commit, error) {
+// Commit returns the commit at the current position.
+func (it *CommitIterator) Commit() Commit {
+	return it.current
+}
+
+// Err returns the first error encountered while paginating, if any.
+func (it *CommitIterator) Err() error {
+	return it.err
+}
+
+// ListCommits collects every commit matching opts, across all pages.
+func (c *Client) ListCommits(ctx context.Context, owner, repo string, opts CommitOptions) ([]Commit, error) {
 	var commits []Commit
-	since := time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
 
-	url := "https://api.github.com/repos/" + owner + "/" + repo + "/commits?since=" + since
-	err := c.get(url, &commits)
-	return commits, err
+	it := c.IterCommits(ctx, owner, repo, opts)
+	for it.Next() {
+		commits = append(commits, it.Commit())
+	}
+	return commits, it.Err()
+}
+
+// GetCommit fetches a single commit including the files it changed.
+func (c *Client) GetCommit(ctx context.Context, owner, repo, sha string) (*Commit, error) {
+	var commit Commit
+	if err := c.get(ctx, c.endpoint("repos/%s/%s/commits/", owner, repo)+escapePath(sha), &commit); err != nil {
+		return nil, err
+	}
+	return &commit, nil
 }
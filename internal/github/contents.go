@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Content is a file returned by the contents API.
+type Content struct {
+	Type     string `json:"type"` // "file", "dir", "symlink" or "submodule"
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	SHA      string `json:"sha"`
+	Size     int    `json:"size"`
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
+// Decode returns the file's bytes.
+func (c *Content) Decode() ([]byte, error) {
+	return decodeContent(c.Encoding, c.Content)
+}
+
+// Blob is a git blob addressed by SHA.
+type Blob struct {
+	SHA      string `json:"sha"`
+	Size     int    `json:"size"`
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
+// Decode returns the blob's bytes.
+func (b *Blob) Decode() ([]byte, error) {
+	return decodeContent(b.Encoding, b.Content)
+}
+
+func decodeContent(encoding, content string) ([]byte, error) {
+	switch encoding {
+	case "base64":
+		// GitHub wraps the payload every 60 characters
+		return base64.StdEncoding.DecodeString(strings.ReplaceAll(content, "\n", ""))
+	case "", "none", "utf-8":
+		return []byte(content), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+// GetContents fetches the file at path on ref; an empty ref means the
+// default branch. Files over 1 MB come back without content.
+func (c *Client) GetContents(ctx context.Context, owner, repo, path, ref string) (*Content, error) {
+	u := c.endpoint("repos/%s/%s/contents/", owner, repo) + escapePath(path)
+	if ref != "" {
+		u += "?ref=" + url.QueryEscape(ref)
+	}
+
+	var content Content
+	if err := c.get(ctx, u, &content); err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+// GetBlob fetches a blob by SHA. Unlike GetContents it works for files up
+// to 100 MB, and the response never changes so it is cached for long.
+func (c *Client) GetBlob(ctx context.Context, owner, repo, sha string) (*Blob, error) {
+	var blob Blob
+	if err := c.get(ctx, c.endpoint("repos/%s/%s/git/blobs/%s", owner, repo, sha), &blob); err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// escapePath escapes each segment of a slash-separated repository path.
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,171 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for errors.Is. *APIError and *RateLimitError match the
+// sentinel for their status code.
+var (
+	ErrNotFound        = errors.New("github: not found")
+	ErrUnauthorized    = errors.New("github: bad or expired credentials")
+	ErrForbidden       = errors.New("github: access forbidden")
+	ErrRateLimited     = errors.New("github: rate limit exceeded")
+	ErrEmptyRepository = errors.New("github: repository is empty")
+	ErrTruncatedTree   = errors.New("github: file tree truncated")
+)
+
+// APIError is a non-2xx response from the API. Message and
+// DocumentationURL are parsed from GitHub's JSON error body.
+type APIError struct {
+	StatusCode       int    `json:"-"`
+	Status           string `json:"-"`
+	URL              string `json:"-"`
+	Message          string `json:"message"`
+	DocumentationURL string `json:"documentation_url"`
+}
+
+func (e *APIError) Error() string {
+	msg := "GitHub API error: " + e.Status
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is maps the status code onto the package sentinels.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrEmptyRepository:
+		// GET /commits answers 409 "Git Repository is empty."
+		return e.StatusCode == http.StatusConflict && strings.Contains(strings.ToLower(e.Message), "empty")
+	}
+	return false
+}
+
+// RateLimitError reports that a request was refused, or not sent at all,
+// because the rate budget ran out or a secondary limit was hit.
+type RateLimitError struct {
+	Limit            int
+	Remaining        int
+	Reset            time.Time     // when the primary budget refills
+	RetryAfter       time.Duration // server-requested wait for secondary limits
+	Secondary        bool
+	Message          string
+	DocumentationURL string
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Secondary {
+		msg := "GitHub API secondary rate limit hit"
+		if e.RetryAfter > 0 {
+			msg += fmt.Sprintf("; retry in %s", e.RetryAfter.Round(time.Second))
+		}
+		return msg
+	}
+	return fmt.Sprintf(
+		"GitHub API rate limit exceeded (%d/%d left); resets at %s",
+		e.Remaining, e.Limit, e.Reset.Local().Format("15:04:05"),
+	)
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// checkResponse converts a non-200 response into *RateLimitError or
+// *APIError.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+	if resp.Request != nil {
+		apiErr.URL = resp.Request.URL.String()
+	}
+	if body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10)); err == nil {
+		json.Unmarshal(body, apiErr)
+	}
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if rlErr := rateLimitError(resp.Header, apiErr); rlErr != nil {
+			return rlErr
+		}
+	}
+	return apiErr
+}
+
+// rateLimitError returns a *RateLimitError if a 403/429 was caused by rate
+// limiting rather than missing permissions.
+func rateLimitError(h http.Header, apiErr *APIError) *RateLimitError {
+	msg := strings.ToLower(apiErr.Message)
+	secondary := strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse")
+	exhausted := h.Get("X-RateLimit-Remaining") == "0"
+	retryAfter := h.Get("Retry-After")
+
+	if !secondary && !exhausted && retryAfter == "" {
+		return nil
+	}
+
+	e := &RateLimitError{
+		Secondary:        secondary || (!exhausted && retryAfter != ""),
+		Message:          apiErr.Message,
+		DocumentationURL: apiErr.DocumentationURL,
+	}
+	e.Limit, _ = strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	e.Remaining, _ = strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		e.Reset = time.Unix(reset, 0)
+	}
+	if secs, err := strconv.Atoi(retryAfter); err == nil {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	}
+	return e
+}
+
+// Guidance returns a one-line suggestion for fixing err, or "" when there
+// is nothing specific to say.
+func Guidance(err error) string {
+	var rlErr *RateLimitError
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &rlErr):
+		if rlErr.Secondary {
+			return "Too many requests in a short time. Wait a minute and try again."
+		}
+		if rlErr.Limit > 0 && rlErr.Limit <= 60 {
+			return "Unauthenticated requests are limited to 60/hour. Set GITHUB_TOKEN for 5000/hour."
+		}
+		return "Wait until " + rlErr.Reset.Local().Format("15:04") + " for the rate limit to reset."
+	case errors.Is(err, ErrUnauthorized):
+		return "GITHUB_TOKEN is invalid or expired. Create a new token or unset the variable."
+	case errors.Is(err, ErrNotFound):
+		return "Check the owner/repo spelling. Private repositories need a GITHUB_TOKEN with access to them."
+	case errors.Is(err, ErrForbidden):
+		return "Your token lacks permission for this resource, or an organization policy blocks it."
+	case errors.Is(err, ErrEmptyRepository):
+		return "The repository has no commits yet, so there is nothing to analyze."
+	case errors.Is(err, ErrTruncatedTree):
+		return "The repository is too large for a single tree request; some files are missing."
+	}
+	return ""
+}
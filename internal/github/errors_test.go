@@ -0,0 +1,141 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// apiResponse is a response to a request for o/r, as checkResponse sees it
+func apiResponse(status int, body string, header map[string]string) *http.Response {
+	resp := response(status, header, body)
+	resp.Status = fmt.Sprintf("%d %s", status, http.StatusText(status))
+	resp.Request, _ = http.NewRequest(http.MethodGet, "https://api.github.com/repos/o/r", nil)
+	return resp
+}
+
+func TestCheckResponse(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrRateLimited, ErrEmptyRepository}
+
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		header    map[string]string
+		want      error // the only sentinel err should match, nil for none
+		rateLimit bool
+	}{
+		{name: "ok", status: 200},
+		{name: "unauthorized", status: 401, body: `{"message":"Bad credentials"}`, want: ErrUnauthorized},
+		{name: "forbidden", status: 403, body: `{"message":"Resource not accessible"}`, want: ErrForbidden},
+		{name: "not found", status: 404, body: `{"message":"Not Found"}`, want: ErrNotFound},
+		{name: "unprocessable", status: 422, body: `{"message":"Validation Failed"}`},
+		{name: "empty repository", status: 409, body: `{"message":"Git Repository is empty."}`, want: ErrEmptyRepository},
+		{name: "conflict", status: 409, body: `{"message":"Merge conflict"}`},
+		{
+			name: "primary rate limit", status: 403, body: `{"message":"API rate limit exceeded"}`,
+			header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Limit": "60"},
+			want:   ErrRateLimited, rateLimit: true,
+		},
+		{
+			name: "secondary rate limit", status: 403, body: `{"message":"You have exceeded a secondary rate limit"}`,
+			want: ErrRateLimited, rateLimit: true,
+		},
+		{
+			name: "too many requests", status: 429, header: map[string]string{"Retry-After": "30"},
+			want: ErrRateLimited, rateLimit: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkResponse(apiResponse(tt.status, tt.body, tt.header))
+			if tt.status == 200 {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("err = nil")
+			}
+			for _, s := range sentinels {
+				if got := errors.Is(err, s); got != (s == tt.want) {
+					t.Errorf("errors.Is(err, %v) = %v", s, got)
+				}
+			}
+			var rlErr *RateLimitError
+			if got := errors.As(err, &rlErr); got != tt.rateLimit {
+				t.Errorf("errors.As(*RateLimitError) = %v, want %v", got, tt.rateLimit)
+			}
+			var apiErr *APIError
+			if !tt.rateLimit {
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.URL == "" {
+					t.Errorf("err = %#v, want *APIError with status and URL", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitErrorFields(t *testing.T) {
+	err := checkResponse(apiResponse(403, `{"message":"API rate limit exceeded","documentation_url":"https://docs"}`, map[string]string{
+		"X-RateLimit-Limit":     "5000",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     "1700000000",
+	}))
+	wrapped := fmt.Errorf("fetching repo: %w", err)
+
+	var rlErr *RateLimitError
+	if !errors.As(wrapped, &rlErr) {
+		t.Fatalf("errors.As through a wrap failed for %v", wrapped)
+	}
+	if !errors.Is(wrapped, ErrRateLimited) {
+		t.Error("wrapped rate limit error does not match ErrRateLimited")
+	}
+	if rlErr.Secondary || rlErr.Limit != 5000 || rlErr.Remaining != 0 ||
+		!rlErr.Reset.Equal(time.Unix(1700000000, 0)) || rlErr.DocumentationURL != "https://docs" {
+		t.Errorf("rate limit error = %+v", rlErr)
+	}
+
+	err = checkResponse(apiResponse(429, "", map[string]string{"Retry-After": "30"}))
+	if !errors.As(err, &rlErr) || !rlErr.Secondary || rlErr.RetryAfter != 30*time.Second {
+		t.Errorf("Retry-After without an exhausted budget = %+v, want secondary with a 30s wait", rlErr)
+	}
+}
+
+func TestGuidance(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string // substring, "" for no guidance
+	}{
+		{"nil", nil, ""},
+		{"secondary", &RateLimitError{Secondary: true}, "short time"},
+		{"unauthenticated", &RateLimitError{Limit: 60}, "GITHUB_TOKEN"},
+		{"authenticated", &RateLimitError{Limit: 5000, Reset: time.Now()}, "Wait until"},
+		{"unauthorized", &APIError{StatusCode: 401}, "invalid or expired"},
+		{"not found", fmt.Errorf("wrapped: %w", &APIError{StatusCode: 404}), "owner/repo"},
+		{"forbidden", &APIError{StatusCode: 403}, "permission"},
+		{"empty", &APIError{StatusCode: 409, Message: "Git Repository is empty."}, "no commits"},
+		{"truncated", ErrTruncatedTree, "too large"},
+		{"unprocessable", &APIError{StatusCode: 422}, ""},
+		{"other", errors.New("boom"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Guidance(tt.err)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("Guidance = %q, want none", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Guidance = %q, want it to mention %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,115 @@
 package github
 
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// User is the subset of a GitHub account returned inside other objects.
+type User struct {
+	Login string `json:"login"`
+	Type  string `json:"type"` // "User" or "Bot"
+}
+
+// Label is an issue or pull request label.
+type Label struct {
+	Name string `json:"name"`
+}
+
 type Issue struct {
-	State string `json:"state"`
+	Number            int        `json:"number"`
+	Title             string     `json:"title"`
+	State             string     `json:"state"`
+	StateReason       string     `json:"state_reason"`
+	User              User       `json:"user"`
+	Labels            []Label    `json:"labels"`
+	Comments          int        `json:"comments"`
+	AuthorAssociation string     `json:"author_association"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	ClosedAt          *time.Time `json:"closed_at"`
+	PullRequest       *struct {
+		URL string `json:"url"`
+	} `json:"pull_request"`
+}
+
+// IsPullRequest reports whether the issues endpoint returned a pull
+// request, which GitHub lists alongside issues.
+func (i Issue) IsPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+// IssueOptions narrows an issue listing.
+type IssueOptions struct {
+	State    string    // "open", "closed" or "all" (default "all")
+	Since    time.Time // only issues updated after this time
+	MaxCount int       // stop after this many issues; 0 means no cap
 }
 
-func (c *Client) GetIssues(owner, repo string, state string) ([]Issue, error) {
+// GetIssues lists issues newest first, following pagination and leaving
+// out pull requests.
+func (c *Client) GetIssues(ctx context.Context, owner, repo string, opts IssueOptions) ([]Issue, error) {
+	q := url.Values{}
+	q.Set("per_page", strconv.Itoa(perPage))
+	q.Set("state", "all")
+	if opts.State != "" {
+		q.Set("state", opts.State)
+	}
+	if !opts.Since.IsZero() {
+		q.Set("since", opts.Since.UTC().Format(time.RFC3339))
+	}
+
 	var issues []Issue
-	url := "https://api.github.com/repos/" + owner + "/" + repo + "/issues?state=" + state
-	err := c.get(url, &issues)
-	return issues, err
+	next := c.endpoint("repos/%s/%s/issues", owner, repo) + "?" + q.Encode()
+	for next != "" {
+		var page []Issue
+		var err error
+		if next, err = c.getPage(ctx, next, &page); err != nil {
+			return issues, err
+		}
+
+		for _, issue := range page {
+			if issue.IsPullRequest() {
+				continue
+			}
+			issues = append(issues, issue)
+			if opts.MaxCount > 0 && len(issues) >= opts.MaxCount {
+				return issues, nil
+			}
+		}
+	}
+	return issues, nil
+}
+
+// IssueComment is a comment on an issue or on a pull request's conversation.
+type IssueComment struct {
+	ID                int64     `json:"id"`
+	IssueURL          string    `json:"issue_url"`
+	User              User      `json:"user"`
+	AuthorAssociation string    `json:"author_association"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// IssueNumber extracts the issue number from IssueURL, or 0.
+func (ic IssueComment) IssueNumber() int {
+	n, _ := strconv.Atoi(ic.IssueURL[strings.LastIndex(ic.IssueURL, "/")+1:])
+	return n
+}
+
+// GetIssueComments lists comments across every issue and pull request of
+// the repository, newest first, back to since. A cap therefore cuts off the
+// oldest comments, not those on recent issues.
+func (c *Client) GetIssueComments(ctx context.Context, owner, repo string, since time.Time, maxCount int) ([]IssueComment, error) {
+	q := url.Values{}
+	q.Set("per_page", strconv.Itoa(perPage))
+	q.Set("sort", "created")
+	q.Set("direction", "desc")
+	if !since.IsZero() {
+		q.Set("since", since.UTC().Format(time.RFC3339))
+	}
+
+	return getUpTo[IssueComment](ctx, c, c.endpoint("repos/%s/%s/issues/comments", owner, repo)+"?"+q.Encode(), maxCount)
 }
@@ -0,0 +1,119 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// DefaultBaseURL is the REST endpoint of github.com.
+	DefaultBaseURL = "https://api.github.com/"
+	// DefaultUploadURL is the upload endpoint of github.com.
+	DefaultUploadURL = "https://uploads.github.com/"
+	// DefaultUserAgent is sent with every request unless overridden.
+	DefaultUserAgent = "Repo-lyzer"
+)
+
+// TokenSource supplies the token used to authenticate each request.
+// An empty token means the request is sent unauthenticated.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token.
+type StaticToken string
+
+func (t StaticToken) Token() (string, error) {
+	return string(t), nil
+}
+
+// EnvToken is a TokenSource that reads the named environment variable on
+// every request.
+type EnvToken string
+
+func (e EnvToken) Token() (string, error) {
+	return os.Getenv(string(e)), nil
+}
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithBaseURL points the client at a different REST endpoint, e.g. a GitHub
+// Enterprise Server instance ("https://ghe.example.com/api/v3/") or a local
+// test server. Unless WithUploadURL is also given, the upload URL of a GHES
+// base ("/api/v3/") is derived from it ("/api/uploads/").
+func WithBaseURL(rawURL string) Option {
+	return func(c *Client) {
+		u, err := parseEndpoint(rawURL)
+		if err != nil {
+			c.configErr = fmt.Errorf("invalid API base URL: %w", err)
+			return
+		}
+		c.baseURL = u
+		if !c.uploadSet && strings.HasSuffix(u.Path, "/api/v3/") {
+			upload := *u
+			upload.Path = strings.TrimSuffix(u.Path, "v3/") + "uploads/"
+			c.uploadURL = &upload
+		}
+	}
+}
+
+// WithUploadURL sets the endpoint used for uploads.
+func WithUploadURL(rawURL string) Option {
+	return func(c *Client) {
+		u, err := parseEndpoint(rawURL)
+		if err != nil {
+			c.configErr = fmt.Errorf("invalid upload URL: %w", err)
+			return
+		}
+		c.uploadURL = u
+		c.uploadSet = true
+	}
+}
+
+// WithHTTPClient replaces the underlying *http.Client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.http = hc
+		}
+	}
+}
+
+// WithUserAgent overrides the User-Agent header.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		if ua != "" {
+			c.userAgent = ua
+		}
+	}
+}
+
+// WithTokenSource sets where the authentication token comes from.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) {
+		c.tokens = ts
+	}
+}
+
+// WithToken authenticates every request with a fixed token.
+func WithToken(token string) Option {
+	return WithTokenSource(StaticToken(token))
+}
+
+func parseEndpoint(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute URL", rawURL)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
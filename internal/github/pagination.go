@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"strings"
+)
+
+// perPage is the page size requested from list endpoints (GitHub's maximum).
+const perPage = 100
+
+// nextPageURL extracts the rel="next" target from a GitHub Link header such as
+//
+//	<https://api.github.com/...&page=2>; rel="next", <https://...&page=5>; rel="last"
+//
+// It returns "" when there is no next page.
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+
+		target := strings.TrimSpace(segments[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return target[1 : len(target)-1]
+			}
+		}
+	}
+	return ""
+}
+
+// getAll follows Link headers from url and collects every item of every page.
+func getAll[T any](ctx context.Context, c *Client, url string) ([]T, error) {
+	var all []T
+
+	for url != "" {
+		var page []T
+		next, err := c.getPage(ctx, url, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, page...)
+		url = next
+	}
+
+	return all, nil
+}
+
+// getUpTo is getAll that stops once max items have been collected; max <= 0
+// means no cap.
+func getUpTo[T any](ctx context.Context, c *Client, url string, max int) ([]T, error) {
+	var all []T
+
+	for url != "" {
+		var page []T
+		next, err := c.getPage(ctx, url, &page)
+		if err != nil {
+			return all, err
+		}
+
+		all = append(all, page...)
+		if max > 0 && len(all) >= max {
+			return all[:max], nil
+		}
+		url = next
+	}
+
+	return all, nil
+}
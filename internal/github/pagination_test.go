@@ -0,0 +1,170 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestClient points an unauthenticated client without retries at a
+// test server.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewClient(WithBaseURL(srv.URL), WithToken(""), WithRetryPolicy(RetryPolicy{}))
+}
+
+// pagedCommits serves pages of pageSize commits, numbered from 0, with
+// Link headers, and counts the requests made.
+func pagedCommits(pages, pageSize int, requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page < pages {
+			next := fmt.Sprintf("http://%s%s?page=%d", r.Host, r.URL.Path, page+1)
+			last := fmt.Sprintf("http://%s%s?page=%d", r.Host, r.URL.Path, pages)
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next", <%s>; rel="last"`, next, last))
+		}
+		commits := make([]Commit, pageSize)
+		for i := range commits {
+			commits[i].SHA = strconv.Itoa((page-1)*pageSize + i)
+		}
+		json.NewEncoder(w).Encode(commits)
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{"empty", "", ""},
+		{
+			"next and last",
+			`<https://api.github.com/repos/o/r/commits?page=2>; rel="next", <https://api.github.com/repos/o/r/commits?page=5>; rel="last"`,
+			"https://api.github.com/repos/o/r/commits?page=2",
+		},
+		{
+			"next not first",
+			`<https://api.github.com/x?page=1>; rel="prev", <https://api.github.com/x?page=3>; rel="next"`,
+			"https://api.github.com/x?page=3",
+		},
+		{"last page", `<https://api.github.com/x?page=1>; rel="first", <https://api.github.com/x?page=4>; rel="prev"`, ""},
+		{"extra spaces", ` <https://api.github.com/x?page=2> ;  rel="next" `, "https://api.github.com/x?page=2"},
+		{"missing brackets", `https://api.github.com/x?page=2; rel="next"`, ""},
+		{"no params", `<https://api.github.com/x?page=2>`, ""},
+		{"other rel", `<https://api.github.com/x?page=2>; rel="nextish"`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL(tt.link); got != tt.want {
+				t.Errorf("nextPageURL(%q) = %q, want %q", tt.link, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetUpTo(t *testing.T) {
+	tests := []struct {
+		name         string
+		max          int
+		wantItems    int
+		wantRequests int32
+	}{
+		{"no cap", 0, 9, 3},
+		{"negative is no cap", -1, 9, 3},
+		{"cap inside first page", 2, 2, 1},
+		{"cap at page boundary", 3, 3, 1},
+		{"cap inside later page", 4, 4, 2},
+		{"cap above total", 50, 9, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			c := newTestClient(t, pagedCommits(3, 3, &requests))
+			got, err := getUpTo[Commit](context.Background(), c, c.endpoint("repos/%s/%s/commits", "o", "r"), tt.max)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.wantItems {
+				t.Errorf("got %d items, want %d", len(got), tt.wantItems)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", requests, tt.wantRequests)
+			}
+			for i, commit := range got {
+				if commit.SHA != strconv.Itoa(i) {
+					t.Errorf("item %d has SHA %q, want items in order", i, commit.SHA)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCommitIteratorMaxCount(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxCount     int
+		wantCommits  int
+		wantRequests int32
+	}{
+		{"no cap", 0, 10, 5},
+		{"cap inside a page", 3, 3, 2},
+		{"cap at page boundary", 4, 4, 2},
+		{"cap above total", 100, 10, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			c := newTestClient(t, pagedCommits(5, 2, &requests))
+			commits, err := c.ListCommits(context.Background(), "o", "r", CommitOptions{MaxCount: tt.maxCount})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(commits) != tt.wantCommits {
+				t.Errorf("got %d commits, want %d", len(commits), tt.wantCommits)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestCommitIteratorError(t *testing.T) {
+	var requests int32
+	pages := pagedCommits(3, 2, &requests)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			http.Error(w, `{"message":"boom"}`, http.StatusNotFound)
+			return
+		}
+		pages(w, r)
+	}))
+
+	it := c.IterCommits(context.Background(), "o", "r", CommitOptions{})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("iterated %d commits before the error, want 2", n)
+	}
+	if it.Err() == nil {
+		t.Error("Err() = nil, want the page 2 error")
+	}
+	if it.Next() {
+		t.Error("Next() after an error = true")
+	}
+}
@@ -0,0 +1,38 @@
+package github
+
+import (
+	"context"
+	"io"
+)
+
+// PageInfo describes one response page read by the client.
+type PageInfo struct {
+	URL       string
+	Bytes     int64
+	FromCache bool
+}
+
+type pageObserverKey struct{}
+
+// WithPageObserver returns a context whose requests report every page they
+// read to fn. fn runs on the goroutine that made the request.
+func WithPageObserver(ctx context.Context, fn func(PageInfo)) context.Context {
+	return context.WithValue(ctx, pageObserverKey{}, fn)
+}
+
+func pageObserver(ctx context.Context) func(PageInfo) {
+	fn, _ := ctx.Value(pageObserverKey{}).(func(PageInfo))
+	return fn
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,102 @@
+package github
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PullRequest is a pull request. Additions, Deletions and ChangedFiles are
+// only filled in by GetPullRequest; list endpoints leave them at 0.
+type PullRequest struct {
+	Number            int        `json:"number"`
+	Title             string     `json:"title"`
+	State             string     `json:"state"`
+	Draft             bool       `json:"draft"`
+	User              User       `json:"user"`
+	AuthorAssociation string     `json:"author_association"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	ClosedAt          *time.Time `json:"closed_at"`
+	MergedAt          *time.Time `json:"merged_at"`
+	Additions         int        `json:"additions"`
+	Deletions         int        `json:"deletions"`
+	ChangedFiles      int        `json:"changed_files"`
+}
+
+// Merged reports whether the pull request was merged.
+func (pr PullRequest) Merged() bool {
+	return pr.MergedAt != nil
+}
+
+// PullRequestOptions narrows a pull request listing.
+type PullRequestOptions struct {
+	State    string // "open", "closed" or "all" (default "all")
+	MaxCount int    // stop after this many pull requests; 0 means no cap
+}
+
+// GetPullRequests lists pull requests newest first, following pagination.
+func (c *Client) GetPullRequests(ctx context.Context, owner, repo string, opts PullRequestOptions) ([]PullRequest, error) {
+	q := url.Values{}
+	q.Set("per_page", strconv.Itoa(perPage))
+	q.Set("state", "all")
+	if opts.State != "" {
+		q.Set("state", opts.State)
+	}
+
+	return getUpTo[PullRequest](ctx, c, c.endpoint("repos/%s/%s/pulls", owner, repo)+"?"+q.Encode(), opts.MaxCount)
+}
+
+// GetPullRequest fetches a single pull request including its size.
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.get(ctx, c.endpoint("repos/%s/%s/pulls/%d", owner, repo, number), &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// Review is a submitted pull request review.
+type Review struct {
+	ID                int64     `json:"id"`
+	User              User      `json:"user"`
+	State             string    `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED
+	AuthorAssociation string    `json:"author_association"`
+	SubmittedAt       time.Time `json:"submitted_at"`
+}
+
+// GetPullRequestReviews lists the reviews of one pull request, oldest first.
+func (c *Client) GetPullRequestReviews(ctx context.Context, owner, repo string, number int) ([]Review, error) {
+	return getAll[Review](ctx, c, c.endpoint("repos/%s/%s/pulls/%d/reviews", owner, repo, number)+"?per_page="+strconv.Itoa(perPage))
+}
+
+// ReviewComment is an inline comment on a pull request's diff.
+type ReviewComment struct {
+	ID             int64     `json:"id"`
+	PullRequestURL string    `json:"pull_request_url"`
+	User           User      `json:"user"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PullNumber extracts the pull request number from PullRequestURL, or 0.
+func (rc ReviewComment) PullNumber() int {
+	n, _ := strconv.Atoi(rc.PullRequestURL[strings.LastIndex(rc.PullRequestURL, "/")+1:])
+	return n
+}
+
+// GetReviewComments lists inline review comments across every pull request
+// of the repository, newest first, back to since. A cap therefore cuts off
+// the oldest comments, not those on recent pull requests.
+func (c *Client) GetReviewComments(ctx context.Context, owner, repo string, since time.Time, maxCount int) ([]ReviewComment, error) {
+	q := url.Values{}
+	q.Set("per_page", strconv.Itoa(perPage))
+	q.Set("sort", "created")
+	q.Set("direction", "desc")
+	if !since.IsZero() {
+		q.Set("since", since.UTC().Format(time.RFC3339))
+	}
+
+	return getUpTo[ReviewComment](ctx, c, c.endpoint("repos/%s/%s/pulls/comments", owner, repo)+"?"+q.Encode(), maxCount)
+}
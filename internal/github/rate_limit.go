@@ -1,6 +1,7 @@
 package github
 
 import (
+	"context"
 	"time"
 )
 type RateLimit struct {
@@ -12,9 +13,9 @@ type RateLimit struct {
 		} `json:"core"`
 	} `json:"resources"`
 }
-func (c *Client) GetRateLimit() (*RateLimit, error) {
+func (c *Client) GetRateLimit(ctx context.Context) (*RateLimit, error) {
 	var rateLimit RateLimit
-	err := c.get("https://api.github.com/rate_limit", &rateLimit)
+	err := c.get(ctx, c.endpoint("rate_limit"), &rateLimit)
 	if err != nil {
 		return nil, err
 	}
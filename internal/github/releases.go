@@ -0,0 +1,43 @@
+package github
+
+import (
+	"context"
+	"time"
+)
+
+// ReleaseAsset is a file attached to a release.
+type ReleaseAsset struct {
+	Name          string `json:"name"`
+	Size          int    `json:"size"`
+	DownloadCount int    `json:"download_count"`
+}
+
+// Release is a published (or draft) GitHub release.
+type Release struct {
+	TagName     string         `json:"tag_name"`
+	Name        string         `json:"name"`
+	Draft       bool           `json:"draft"`
+	Prerelease  bool           `json:"prerelease"`
+	CreatedAt   time.Time      `json:"created_at"`
+	PublishedAt time.Time      `json:"published_at"`
+	HTMLURL     string         `json:"html_url"`
+	Assets      []ReleaseAsset `json:"assets"`
+}
+
+// Tag is a git tag as listed by the tags endpoint.
+type Tag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+// GetReleases fetches ALL releases, newest first (paginated)
+func (c *Client) GetReleases(ctx context.Context, owner, repo string) ([]Release, error) {
+	return getAll[Release](ctx, c, c.endpoint("repos/%s/%s/releases?per_page=%d", owner, repo, perPage))
+}
+
+// GetTags fetches ALL tags (paginated)
+func (c *Client) GetTags(ctx context.Context, owner, repo string) ([]Tag, error) {
+	return getAll[Tag](ctx, c, c.endpoint("repos/%s/%s/tags?per_page=%d", owner, repo, perPage))
+}
@@ -0,0 +1,195 @@
+package github
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how the client reacts to rate limiting and transient
+// server errors.
+type RetryPolicy struct {
+	// MaxRetries is how many times a request is re-sent after a 429, a
+	// secondary-rate-limit 403 or a 5xx response.
+	MaxRetries int
+	// BaseDelay is the first exponential backoff step for 5xx responses.
+	BaseDelay time.Duration
+	// MaxWait caps any single wait. When the rate budget resets later than
+	// this, the request fails instead of blocking.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy is used unless WithRetryPolicy says otherwise.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  time.Second,
+	MaxWait:    time.Minute,
+}
+
+// WithRetryPolicy overrides DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// rateLimitTransport tracks the core rate budget from response headers and
+// delays or retries requests that GitHub throttled.
+type rateLimitTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+
+	mu     sync.Mutex
+	budget RateLimit
+	known  bool
+}
+
+func newRateLimitTransport(next http.RoundTripper, policy RetryPolicy) *rateLimitTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &rateLimitTransport{next: next, policy: policy}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.waitForBudget(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.observe(resp.Header)
+
+		delay, retry := t.retryDelay(resp, attempt)
+		if !retry || attempt >= t.policy.MaxRetries || delay > t.policy.MaxWait {
+			return resp, nil
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err := sleep(req, jitter(delay)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitForBudget blocks until the core budget resets when the last response
+// said it was exhausted.
+func (t *rateLimitTransport) waitForBudget(req *http.Request) error {
+	budget, ok := t.Budget()
+	if !ok || budget.Resources.Core.Remaining > 0 {
+		return nil
+	}
+
+	wait := time.Until(budget.ResetTime())
+	if wait <= 0 {
+		return nil
+	}
+	if wait > t.policy.MaxWait {
+		return &RateLimitError{
+			Limit:     budget.Resources.Core.Limit,
+			Remaining: 0,
+			Reset:     budget.ResetTime(),
+		}
+	}
+	return sleep(req, wait)
+}
+
+// retryDelay decides whether resp should be retried and after how long.
+func (t *rateLimitTransport) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden:
+		if after := resp.Header.Get("Retry-After"); after != "" {
+			if secs, err := strconv.Atoi(after); err == nil {
+				return time.Duration(secs) * time.Second, true
+			}
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+				return time.Until(time.Unix(reset, 0)), true
+			}
+		}
+		if resp.StatusCode == http.StatusForbidden && !isSecondaryRateLimit(resp) {
+			return 0, false
+		}
+		// GitHub asks clients to wait at least a minute when a secondary
+		// limit comes without Retry-After.
+		return time.Minute << attempt, true
+
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return t.policy.BaseDelay << attempt, true
+	}
+	return 0, false
+}
+
+// isSecondaryRateLimit peeks at a 403 body for GitHub's abuse-detection
+// message, restoring the body for later readers.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	msg := strings.ToLower(string(body))
+	return strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse detection")
+}
+
+// observe records the rate budget advertised by a response.
+func (t *rateLimitTransport) observe(h http.Header) {
+	if resource := h.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+
+	limit, err1 := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	remaining, err2 := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, err3 := strconv.Atoi(h.Get("X-RateLimit-Reset"))
+	if err1 != nil || err2 != nil || err3 != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.budget.Resources.Core.Limit = limit
+	t.budget.Resources.Core.Remaining = remaining
+	t.budget.Resources.Core.Reset = reset
+	t.known = true
+}
+
+// Budget returns the most recently observed core budget.
+func (t *rateLimitTransport) Budget() (RateLimit, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.budget, t.known
+}
+
+// jitter adds up to 10% random delay so concurrent clients don't retry in
+// lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/10+1))
+}
+
+// sleep waits for d or until the request is cancelled.
+func sleep(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
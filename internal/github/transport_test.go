@@ -0,0 +1,215 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func response(status int, header map[string]string, body string) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+	for k, v := range header {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestRetryDelay(t *testing.T) {
+	tr := newRateLimitTransport(nil, RetryPolicy{MaxRetries: 3, BaseDelay: 100 * time.Millisecond, MaxWait: time.Hour})
+	reset := strconv.FormatInt(time.Now().Add(30*time.Second).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		resp      *http.Response
+		attempt   int
+		wantRetry bool
+		min, max  time.Duration
+	}{
+		{"ok", response(200, nil, ""), 0, false, 0, 0},
+		{"not found", response(404, nil, ""), 0, false, 0, 0},
+		{"429 retry-after", response(429, map[string]string{"Retry-After": "7"}, ""), 0, true, 7 * time.Second, 7 * time.Second},
+		{"403 retry-after", response(403, map[string]string{"Retry-After": "2"}, ""), 1, true, 2 * time.Second, 2 * time.Second},
+		{"exhausted budget waits for reset", response(403, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}, ""), 0, true, 28 * time.Second, 30 * time.Second},
+		{"secondary limit without retry-after", response(403, nil, `{"message":"You have exceeded a secondary rate limit"}`), 0, true, time.Minute, time.Minute},
+		{"secondary limit backs off", response(403, nil, `{"message":"abuse detection mechanism"}`), 2, true, 4 * time.Minute, 4 * time.Minute},
+		{"plain forbidden", response(403, nil, `{"message":"Resource not accessible"}`), 0, false, 0, 0},
+		{"429 without headers", response(429, nil, ""), 0, true, time.Minute, time.Minute},
+		{"500 first attempt", response(500, nil, ""), 0, true, 100 * time.Millisecond, 100 * time.Millisecond},
+		{"502 third attempt", response(502, nil, ""), 2, true, 400 * time.Millisecond, 400 * time.Millisecond},
+		{"501 is not transient", response(501, nil, ""), 0, false, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retry := tr.retryDelay(tt.resp, tt.attempt)
+			if retry != tt.wantRetry {
+				t.Fatalf("retry = %v, want %v", retry, tt.wantRetry)
+			}
+			if delay < tt.min || delay > tt.max {
+				t.Errorf("delay = %v, want %v..%v", delay, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryDelayKeepsForbiddenBody(t *testing.T) {
+	tr := newRateLimitTransport(nil, DefaultRetryPolicy)
+	resp := response(403, nil, `{"message":"Resource not accessible"}`)
+	tr.retryDelay(resp, 0)
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"message":"Resource not accessible"}` {
+		t.Errorf("body after peeking = %q", body)
+	}
+}
+
+func TestJitterBounds(t *testing.T) {
+	if got := jitter(0); got != 0 {
+		t.Errorf("jitter(0) = %v, want 0", got)
+	}
+	if got := jitter(-time.Second); got != 0 {
+		t.Errorf("jitter(-1s) = %v, want 0", got)
+	}
+	for _, d := range []time.Duration{time.Nanosecond, 9 * time.Nanosecond, time.Millisecond, time.Second, time.Minute} {
+		for i := 0; i < 1000; i++ {
+			if got := jitter(d); got < d || got > d+d/10 {
+				t.Fatalf("jitter(%v) = %v, want within [%v, %v]", d, got, d, d+d/10)
+			}
+		}
+	}
+}
+
+// flakyServer fails the first failures requests with status and header,
+// then answers 200.
+func flakyServer(t *testing.T, failures int32, status int, header map[string]string, body string) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			for k, v := range header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			io.WriteString(w, body)
+			return
+		}
+		io.WriteString(w, "{}")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestRoundTripRetries(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxWait: time.Second}
+	tests := []struct {
+		name         string
+		failures     int32
+		status       int
+		header       map[string]string
+		body         string
+		wantStatus   int
+		wantRequests int32
+	}{
+		{"5xx recovers", 2, 503, nil, "", 200, 3},
+		{"5xx gives up after MaxRetries", 5, 500, nil, "", 500, 3},
+		{"429 with retry-after recovers", 1, 429, map[string]string{"Retry-After": "0"}, "", 200, 2},
+		{"secondary limit with retry-after recovers", 1, 403, map[string]string{"Retry-After": "0"}, `{"message":"secondary rate limit"}`, 200, 2},
+		{"secondary limit longer than MaxWait is returned", 1, 403, nil, `{"message":"secondary rate limit"}`, 403, 1},
+		{"plain 403 is not retried", 1, 403, nil, `{"message":"nope"}`, 403, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := flakyServer(t, tt.failures, tt.status, tt.header, tt.body)
+			client := &http.Client{Transport: newRateLimitTransport(nil, policy)}
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if *requests != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", *requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func budgetHeader(remaining int, reset time.Time) http.Header {
+	h := http.Header{}
+	h.Set("X-RateLimit-Limit", "60")
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	return h
+}
+
+func TestWaitForBudget(t *testing.T) {
+	policy := RetryPolicy{MaxWait: time.Minute}
+	req := httptest.NewRequest("GET", "/", nil)
+
+	t.Run("unknown budget", func(t *testing.T) {
+		tr := newRateLimitTransport(nil, policy)
+		if err := tr.waitForBudget(req); err != nil {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("budget left", func(t *testing.T) {
+		tr := newRateLimitTransport(nil, policy)
+		tr.observe(budgetHeader(5, time.Now().Add(time.Hour)))
+		if err := tr.waitForBudget(req); err != nil {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("reset already passed", func(t *testing.T) {
+		tr := newRateLimitTransport(nil, policy)
+		tr.observe(budgetHeader(0, time.Now().Add(-time.Minute)))
+		if err := tr.waitForBudget(req); err != nil {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("reset beyond MaxWait fails fast", func(t *testing.T) {
+		tr := newRateLimitTransport(nil, policy)
+		tr.observe(budgetHeader(0, time.Now().Add(time.Hour)))
+		err := tr.waitForBudget(req)
+		var rateErr *RateLimitError
+		if !errors.As(err, &rateErr) {
+			t.Fatalf("err = %v, want a *RateLimitError", err)
+		}
+		if rateErr.Limit != 60 {
+			t.Errorf("Limit = %d, want 60", rateErr.Limit)
+		}
+	})
+
+	t.Run("reset within MaxWait waits until cancelled", func(t *testing.T) {
+		tr := newRateLimitTransport(nil, policy)
+		tr.observe(budgetHeader(0, time.Now().Add(30*time.Second)))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := tr.waitForBudget(req.WithContext(ctx)); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err = %v, want the context deadline", err)
+		}
+	})
+
+	t.Run("other resources are ignored", func(t *testing.T) {
+		tr := newRateLimitTransport(nil, policy)
+		h := budgetHeader(0, time.Now().Add(time.Hour))
+		h.Set("X-RateLimit-Resource", "search")
+		tr.observe(h)
+		if _, ok := tr.Budget(); ok {
+			t.Error("search budget was recorded as the core budget")
+		}
+	})
+}
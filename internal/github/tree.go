@@ -1,5 +1,7 @@
 package github
 
+import "context"
+
 type TreeEntry struct {
 	Path string `json:"path"`
 	Mode string `json:"mode"`
@@ -15,9 +17,24 @@ type TreeResponse struct {
 	Truncated bool        `json:"truncated"`
 }
 
-func (c *Client) GetFileTree(owner, repo, branch string) ([]TreeEntry, error) {
+// GetFileTree returns every entry under branch. When GitHub truncates the
+// listing the partial entries are returned together with ErrTruncatedTree.
+func (c *Client) GetFileTree(ctx context.Context, owner, repo, branch string) ([]TreeEntry, error) {
 	var t TreeResponse
 	// recursive=1 to get full tree
-	err := c.get("https://api.github.com/repos/"+owner+"/"+repo+"/git/trees/"+branch+"?recursive=1", &t)
+	err := c.get(ctx, c.endpoint("repos/%s/%s/git/trees/", owner, repo)+escapePath(branch)+"?recursive=1", &t)
+	if err == nil && t.Truncated {
+		err = ErrTruncatedTree
+	}
 	return t.Tree, err
 }
+
+// GetTree returns one level of the tree identified by sha, for walking a
+// repository whose recursive listing was truncated.
+func (c *Client) GetTree(ctx context.Context, owner, repo, sha string) (*TreeResponse, error) {
+	var t TreeResponse
+	if err := c.get(ctx, c.endpoint("repos/%s/%s/git/trees/", owner, repo)+escapePath(sha), &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
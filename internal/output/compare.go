@@ -0,0 +1,87 @@
+package output
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+)
+
+// leaderMark follows the best value of each metric. Colour would be lost on
+// NO_COLOR terminals and throws off tablewriter's column widths.
+const leaderMark = " ★"
+
+// PrintComparison prints the metric matrix, the normalized scores and the
+// weighted ranking of a comparison
+func PrintComparison(c analysis.Comparison) {
+	header := append([]string{"Metric · " + c.Window.Name}, c.Repos...)
+
+	fmt.Println(SectionStyle.Render("\n📊 Repository Comparison (" + c.Window.Label() + ")"))
+	table := newCompareTable()
+	table.Header(header)
+	for _, m := range c.Metrics {
+		row := []string{m.Name}
+		for i, label := range m.Labels {
+			if m.IsLeader(i) {
+				label += leaderMark
+			}
+			row = append(row, label)
+		}
+		table.Append(row)
+	}
+	table.Render()
+	fmt.Println("★ best in the set")
+	for i, a := range c.Activity {
+		if a.Truncated {
+			fmt.Printf("+ %s: only the latest %d commits, back to %s\n", c.Repos[i], a.Commits, a.Start.Format("2006-01-02"))
+		}
+	}
+
+	fmt.Println(SectionStyle.Render("\n🎯 Scores (0-100)"))
+	header[0] = "Dimension · " + c.Window.Name
+	table = newCompareTable()
+	table.Header(header)
+	for _, d := range analysis.Dimensions {
+		row := []string{fmt.Sprintf("%s ×%g", d.Label(), c.Weights[d])}
+		for _, s := range c.Scores {
+			row = append(row, scoreLabel(s, d))
+		}
+		table.Append(row)
+	}
+	overall := []string{"Overall"}
+	for _, s := range c.Scores {
+		overall = append(overall, fmt.Sprintf("%.0f", s.Overall))
+	}
+	table.Append(overall)
+	table.Render()
+
+	fmt.Println(SectionStyle.Render("\n🏆 Ranking"))
+	for _, i := range c.Ranking {
+		s := c.Scores[i]
+		fmt.Printf("%d. %s (%.0f)\n", s.Rank, s.Repo, s.Overall)
+	}
+
+	fmt.Println(SectionStyle.Render("\n📌 Verdict"))
+	if leader := c.Leader(); leader != "" {
+		fmt.Printf("➡️ %s comes out ahead overall.\n", leader)
+	} else {
+		fmt.Println("➡️ The top repositories are tied overall.")
+	}
+}
+
+// newCompareTable keeps repository names in the header as they are typed
+func newCompareTable() *tablewriter.Table {
+	return tablewriter.NewTable(os.Stdout, tablewriter.WithHeaderAutoFormat(tw.Off))
+}
+
+// scoreLabel shows one dimension's score, or "-" without data
+func scoreLabel(s analysis.RepoScore, d analysis.Dimension) string {
+	score, ok := s.Scores[d]
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f", score)
+}
@@ -0,0 +1,25 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// PrintError prints err to stderr followed by a suggestion for fixing it and
+// a link to GitHub's documentation when the API provided one. Stdout is left
+// to the report, which may be JSON or YAML.
+func PrintError(err error) {
+	fmt.Fprintln(os.Stderr, ErrorStyle.Render("❌ "+err.Error()))
+
+	if hint := github.Guidance(err); hint != "" {
+		fmt.Fprintln(os.Stderr, WarningStyle.Render("💡 "+hint))
+	}
+
+	var apiErr *github.APIError
+	if errors.As(err, &apiErr) && apiErr.DocumentationURL != "" {
+		fmt.Fprintln(os.Stderr, "📖 "+apiErr.DocumentationURL)
+	}
+}
@@ -0,0 +1,158 @@
+package output
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatYAML   = "yaml"
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Formats lists every output format, text first
+var Formats = []string{FormatText, FormatJSON, FormatYAML, FormatCSV, FormatNDJSON}
+
+// ReportSchema is the JSON Schema of Report
+//
+//go:embed report.schema.json
+var ReportSchema []byte
+
+// ValidFormat reports whether format is one of Formats
+func ValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteReport encodes the report in a machine-readable format:
+//
+//	json    indented JSON
+//	yaml    YAML with the same field names as JSON
+//	csv     one field,value row per leaf, with dotted paths
+//	ndjson  the report on a single line, so runs can be appended
+func WriteReport(w io.Writer, report Report, format string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+
+	case FormatNDJSON:
+		return json.NewEncoder(w).Encode(report)
+
+	case FormatYAML:
+		// JSON is YAML, so decoding it keeps the JSON field names and order
+		data, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		clearStyle(&doc)
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return err
+		}
+		return enc.Close()
+
+	case FormatCSV:
+		data, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		rows := [][]string{{"field", "value"}}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := flatten(dec, "", &rows); err != nil {
+			return err
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+	return fmt.Errorf("format %q cannot encode a report", format)
+}
+
+// clearStyle drops the flow style YAML gives nodes decoded from JSON, so
+// the output is written block style
+func clearStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// flatten walks one JSON value from dec, appending a row for each leaf
+// named by its dotted path; array elements are numbered from 0
+func flatten(dec *json.Decoder, path string, rows *[][]string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if err := flatten(dec, joinPath(path, key.(string)), rows); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := flatten(dec, joinPath(path, strconv.Itoa(i)), rows); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+	}
+
+	var value string
+	switch v := tok.(type) {
+	case nil:
+		value = ""
+	case string:
+		value = v
+	case bool:
+		value = strconv.FormatBool(v)
+	case json.Number:
+		value = v.String()
+	}
+	*rows = append(*rows, []string{path, value})
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return strings.Join([]string{path, key}, ".")
+}
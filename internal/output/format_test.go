@@ -0,0 +1,159 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func testReport() Report {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	return Report{
+		SchemaVersion: ReportSchemaVersion,
+		GeneratedAt:   time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+		Repository: ReportRepository{
+			FullName:    "o/r",
+			Description: "true", // a string YAML would otherwise read as a bool
+			Language:    "123",
+			Stars:       42,
+			Archived:    true,
+			CreatedAt:   created,
+			PushedAt:    created.AddDate(1, 0, 0),
+		},
+		Languages: []ReportLanguage{
+			{Name: "Go", Bytes: 900, Percent: 90},
+			{Name: "Shell, \"quoted\"", Bytes: 100, Percent: 10},
+		},
+		BusFactor: &ReportBusFactor{Value: 1, Risk: "High Risk", Mode: "heuristic", KeyPeople: []ReportKeyPerson{{Login: "a"}}},
+		Errors:    []ReportError{{Fetch: "issues", Message: "multi\nline: error"}},
+	}
+}
+
+func TestWriteReportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, testReport(), FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if !reflect.DeepEqual(rows[0], []string{"field", "value"}) {
+		t.Errorf("header = %q", rows[0])
+	}
+
+	var fields []string
+	values := map[string]string{}
+	for _, row := range rows[1:] {
+		fields = append(fields, row[0])
+		values[row[0]] = row[1]
+	}
+	want := map[string]string{
+		"schema_version":                "2",
+		"generated_at":                  "2026-10-17T12:00:00Z",
+		"repository.full_name":          "o/r",
+		"repository.stars":              "42",
+		"repository.archived":           "true",
+		"repository.fork":               "false",
+		"languages.0.name":              "Go",
+		"languages.1.name":              `Shell, "quoted"`,
+		"languages.1.percent":           "10",
+		"bus_factor.key_people.0.login": "a",
+		"errors.0.message":              "multi\nline: error",
+	}
+	for field, value := range want {
+		if got, ok := values[field]; !ok || got != value {
+			t.Errorf("%s = %q (present %v), want %q", field, got, ok, value)
+		}
+	}
+	// Sections left out of the report have no rows
+	for _, f := range fields {
+		if strings.HasPrefix(f, "health") || strings.HasPrefix(f, "activity") {
+			t.Errorf("unexpected row %q", f)
+		}
+	}
+
+	// Rows follow the JSON field order, with slice indexes ascending
+	index := func(field string) int {
+		for i, f := range fields {
+			if f == field {
+				return i
+			}
+		}
+		t.Fatalf("no %q row", field)
+		return -1
+	}
+	order := []string{"schema_version", "repository.full_name", "languages.0.name", "languages.0.percent", "languages.1.name", "bus_factor.value", "errors.0.fetch"}
+	for i := 1; i < len(order); i++ {
+		if index(order[i-1]) >= index(order[i]) {
+			t.Errorf("%s comes after %s", order[i-1], order[i])
+		}
+	}
+}
+
+func TestWriteReportYAMLRoundTrips(t *testing.T) {
+	report := testReport()
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, report, FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "schema_version: 2\n") {
+		t.Errorf("YAML does not start with the schema version:\n%s", out)
+	}
+	if strings.Contains(out, "{") {
+		t.Errorf("YAML uses flow style:\n%s", out)
+	}
+
+	var decoded Report
+	if err := yaml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("YAML does not decode: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(decoded, report) {
+		t.Errorf("round trip changed the report:\ngot  %+v\nwant %+v", decoded, report)
+	}
+}
+
+func TestWriteReportNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, testReport(), FormatNDJSON); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasSuffix(out, "\n") || strings.Count(out, "\n") != 1 {
+		t.Fatalf("NDJSON is not one line ending in a newline: %q", out)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Repository.FullName != "o/r" || decoded.Errors[0].Message != "multi\nline: error" {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	// Appended runs stay one per line
+	WriteReport(&buf, testReport(), FormatNDJSON)
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Errorf("two runs wrote %d lines", n)
+	}
+}
+
+func TestWriteReportFormats(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatYAML, FormatCSV, FormatNDJSON} {
+		if err := WriteReport(&bytes.Buffer{}, testReport(), format); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+	}
+	if err := WriteReport(&bytes.Buffer{}, testReport(), FormatText); err == nil {
+		t.Error("text format encoded a report")
+	}
+	if !ValidFormat(FormatNDJSON) || ValidFormat("xml") {
+		t.Error("ValidFormat disagrees with Formats")
+	}
+}
@@ -1,15 +1,26 @@
 package output
 
 import (
+	"context"
 	"fmt"
-	"os"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 	"github.com/agnivo988/Repo-lyzer/internal/github"
 )
 
 
 
+// healthLabel names a health score band, as PrintHealth does
+func healthLabel(score int) string {
+	if score >= 80 {
+		return "Excellent"
+	} else if score >= 60 {
+		return "Good"
+	}
+	return "Poor"
+}
+
 func PrintHealth(score int) {
     color := "#FF5F5F"
 	label:= "🔴 Poor"
@@ -28,17 +39,50 @@ func PrintHealth(score int) {
 		fmt.Sprintf("\n🏆 Repo Health Score : %d/100 (%s)\n",score,label),
 	 ))
 }
-func PrintGitHubAPIStatus(client *github.Client) {
-	rateLimit, err := client.GetRateLimit()
-	if err != nil {
+
+// PrintHealthBreakdown lists the points each rule of the profile gave
+func PrintHealthBreakdown(report analyzer.HealthReport) {
+	fmt.Printf("Profile: %s\n", report.Profile)
+	for _, c := range report.Checks {
+		if c.Skipped {
+			fmt.Printf("  ❔ %-22s not checked\n", c.Name)
+			continue
+		}
+		mark := "➖"
+		switch {
+		case c.Points >= c.MaxPoints:
+			mark = "✅"
+		case c.Points == 0:
+			mark = "❌"
+		}
+		fmt.Printf("  %s %-22s %4g/%-3g %s\n", mark, c.Name, c.Points, c.MaxPoints, c.Detail)
+	}
+}
+
+// PrintMaturity prints the maturity level and the signals behind it
+func PrintMaturity(m analyzer.Maturity) {
+	fmt.Println(SectionStyle.Render("\n🏗️ Maturity"))
+	fmt.Printf("%s (%d/100), %s confidence (%.0f%% of signals)\n", m.Level, m.Score, m.ConfidenceLabel(), m.Confidence*100)
+	for _, s := range m.Signals {
+		if !s.Known {
+			fmt.Printf("  ❔ %-22s not checked\n", s.Name)
+			continue
+		}
+		fmt.Printf("  %-25s %3d  %s\n", s.Name, s.Score, s.Detail)
+	}
+}
+
+func PrintGitHubAPIStatus(ctx context.Context, client *github.Client) {
+	status := NewReportAPIStatus(ctx, client)
+	if status == nil {
 		fmt.Println("⚠️ Unable to fetch GitHub API status")
 		return
 	}
 
 	mode := "Unauthenticated"
-if os.Getenv("GITHUB_TOKEN") != "" {
-	mode = "Authenticated"
-}
+	if status.Authenticated {
+		mode = "Authenticated"
+	}
 
 
 	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7AE7C7"))
@@ -47,11 +91,11 @@ if os.Getenv("GITHUB_TOKEN") != "" {
 	fmt.Printf("Mode        : %s\n", mode)
 	fmt.Printf(
 		"Requests    : %d / %d\n",
-		rateLimit.Resources.Core.Remaining,
-		rateLimit.Resources.Core.Limit,
+		status.Remaining,
+		status.Limit,
 	)
 	fmt.Printf(
 		"Resets At   : %s\n\n",
-		rateLimit.ResetTime().Format("15:04"),
+		status.ResetAt.Local().Format("15:04"),
 	)
 }
@@ -21,4 +21,10 @@ func PrintRecruiterSummary(s analyzer.RecruiterSummary) {
 	fmt.Println("🏗️ Maturity:", s.MaturityLevel, "(", s.MaturityScore, ")")
 	fmt.Println("⚠️ Bus Factor:", s.BusFactor, "-", s.BusRisk)
 	fmt.Println("🔥 Activity:", s.ActivityLevel)
+	if s.IssueHealth != "" {
+		fmt.Println("🐛 Issue Health:", s.IssueHealth)
+	}
+	if s.PRHealth != "" {
+		fmt.Println("🔀 PR Health:", s.PRHealth)
+	}
 }
@@ -0,0 +1,340 @@
+package output
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// ReportSchemaVersion is bumped whenever a field of Report is renamed,
+// removed or changes meaning. Adding fields does not bump it.
+const ReportSchemaVersion = 2
+
+// Report is the machine-readable result of analyzing one repository. Its
+// JSON form is described by the schema printed by `repolyzer schema`. The
+// repository and errors are always present; every other field belongs to a
+// section and is left out unless that section was requested.
+type Report struct {
+	SchemaVersion int              `json:"schema_version" yaml:"schema_version"`
+	GeneratedAt   time.Time        `json:"generated_at" yaml:"generated_at"`
+	Repository    ReportRepository `json:"repository" yaml:"repository"`
+	Languages     []ReportLanguage `json:"languages,omitempty" yaml:"languages,omitempty"`
+	Activity      *ReportActivity  `json:"activity,omitempty" yaml:"activity,omitempty"`
+	Health        *ReportHealth    `json:"health,omitempty" yaml:"health,omitempty"`
+	BusFactor     *ReportBusFactor `json:"bus_factor,omitempty" yaml:"bus_factor,omitempty"`
+	Maturity      *ReportMaturity  `json:"maturity,omitempty" yaml:"maturity,omitempty"`
+	Recruiter     *ReportRecruiter `json:"recruiter_summary,omitempty" yaml:"recruiter_summary,omitempty"`
+	APIStatus     *ReportAPIStatus `json:"api_status,omitempty" yaml:"api_status,omitempty"` // also left out when the rate limit is unknown
+	Errors        []ReportError    `json:"errors" yaml:"errors"`                             // data that could not be fetched
+}
+
+type ReportRepository struct {
+	FullName      string    `json:"full_name" yaml:"full_name"`
+	Description   string    `json:"description" yaml:"description"`
+	URL           string    `json:"url" yaml:"url"`
+	DefaultBranch string    `json:"default_branch" yaml:"default_branch"`
+	Language      string    `json:"language" yaml:"language"`
+	Stars         int       `json:"stars" yaml:"stars"`
+	Forks         int       `json:"forks" yaml:"forks"`
+	Watchers      int       `json:"watchers" yaml:"watchers"`
+	OpenIssues    int       `json:"open_issues" yaml:"open_issues"`
+	Fork          bool      `json:"fork" yaml:"fork"`
+	Archived      bool      `json:"archived" yaml:"archived"`
+	CreatedAt     time.Time `json:"created_at" yaml:"created_at"`
+	PushedAt      time.Time `json:"pushed_at" yaml:"pushed_at"`
+}
+
+type ReportLanguage struct {
+	Name    string  `json:"name" yaml:"name"`
+	Bytes   int     `json:"bytes" yaml:"bytes"`
+	Percent float64 `json:"percent" yaml:"percent"`
+}
+
+type ReportActivity struct {
+	Commits    int         `json:"commits" yaml:"commits"`
+	ActiveDays int         `json:"active_days" yaml:"active_days"`
+	Daily      []ReportDay `json:"daily" yaml:"daily"` // days with at least one commit, oldest first
+}
+
+type ReportDay struct {
+	Date    string `json:"date" yaml:"date"` // YYYY-MM-DD
+	Commits int    `json:"commits" yaml:"commits"`
+}
+
+type ReportHealth struct {
+	Score        int                 `json:"score" yaml:"score"`
+	Label        string              `json:"label" yaml:"label"`
+	Profile      string              `json:"profile" yaml:"profile"`
+	Breakdown    []ReportHealthCheck `json:"breakdown" yaml:"breakdown"`
+	Issues       ReportIssueHealth   `json:"issues" yaml:"issues"`
+	PullRequests ReportPullsHealth   `json:"pull_requests" yaml:"pull_requests"`
+}
+
+type ReportHealthCheck struct {
+	Rule      string  `json:"rule" yaml:"rule"`
+	Name      string  `json:"name" yaml:"name"`
+	Points    float64 `json:"points" yaml:"points"`
+	MaxPoints float64 `json:"max_points" yaml:"max_points"`
+	Skipped   bool    `json:"skipped" yaml:"skipped"`
+	Detail    string  `json:"detail" yaml:"detail"`
+}
+
+type ReportIssueHealth struct {
+	Score                   int     `json:"score" yaml:"score"`
+	Grade                   string  `json:"grade" yaml:"grade"`
+	Verdict                 string  `json:"verdict" yaml:"verdict"`
+	Total                   int     `json:"total" yaml:"total"`
+	Open                    int     `json:"open" yaml:"open"`
+	Closed                  int     `json:"closed" yaml:"closed"`
+	Stale                   int     `json:"stale" yaml:"stale"`
+	MedianCloseDays         float64 `json:"median_close_days" yaml:"median_close_days"`
+	MedianFirstResponseDays float64 `json:"median_first_response_days" yaml:"median_first_response_days"`
+	ResponseKnown           int     `json:"response_known" yaml:"response_known"`
+	Responded               int     `json:"responded" yaml:"responded"`
+	ResponsePartial         bool    `json:"response_partial" yaml:"response_partial"`
+}
+
+type ReportPullsHealth struct {
+	Score              int     `json:"score" yaml:"score"`
+	Grade              string  `json:"grade" yaml:"grade"`
+	Verdict            string  `json:"verdict" yaml:"verdict"`
+	Total              int     `json:"total" yaml:"total"`
+	Open               int     `json:"open" yaml:"open"`
+	Merged             int     `json:"merged" yaml:"merged"`
+	MergeRate          float64 `json:"merge_rate" yaml:"merge_rate"`
+	MedianMergeDays    float64 `json:"median_merge_days" yaml:"median_merge_days"`
+	ReviewCoverage     float64 `json:"review_coverage" yaml:"review_coverage"`
+	ExternalAcceptance float64 `json:"external_acceptance" yaml:"external_acceptance"`
+	Sampled            int     `json:"sampled" yaml:"sampled"`
+	SampledMerged      int     `json:"sampled_merged" yaml:"sampled_merged"`
+	ReviewPartial      bool    `json:"review_partial" yaml:"review_partial"`
+}
+
+type ReportBusFactor struct {
+	Value     int               `json:"value" yaml:"value"`
+	Risk      string            `json:"risk" yaml:"risk"`
+	Mode      string            `json:"mode" yaml:"mode"` // "authorship" or "heuristic"
+	KeyPeople []ReportKeyPerson `json:"key_people" yaml:"key_people"`
+}
+
+type ReportKeyPerson struct {
+	Login string  `json:"login" yaml:"login"`
+	Files int     `json:"files" yaml:"files"` // 0 in heuristic mode
+	Share float64 `json:"share" yaml:"share"`
+}
+
+type ReportMaturity struct {
+	Score      int                    `json:"score" yaml:"score"`
+	Level      string                 `json:"level" yaml:"level"`
+	Confidence float64                `json:"confidence" yaml:"confidence"` // share of signal weight with data, 0-1
+	Signals    []ReportMaturitySignal `json:"signals" yaml:"signals"`
+}
+
+type ReportMaturitySignal struct {
+	Signal string  `json:"signal" yaml:"signal"`
+	Name   string  `json:"name" yaml:"name"`
+	Score  int     `json:"score" yaml:"score"`
+	Weight float64 `json:"weight" yaml:"weight"`
+	Known  bool    `json:"known" yaml:"known"`
+	Detail string  `json:"detail" yaml:"detail"`
+}
+
+type ReportRecruiter struct {
+	ActivityLevel   string `json:"activity_level" yaml:"activity_level"`
+	CommitsLastYear int    `json:"commits_last_year" yaml:"commits_last_year"`
+	Contributors    int    `json:"contributors" yaml:"contributors"`
+	IssueHealth     string `json:"issue_health" yaml:"issue_health"`
+	PRHealth        string `json:"pr_health" yaml:"pr_health"`
+}
+
+type ReportAPIStatus struct {
+	Authenticated bool      `json:"authenticated" yaml:"authenticated"`
+	Limit         int       `json:"limit" yaml:"limit"`
+	Remaining     int       `json:"remaining" yaml:"remaining"`
+	ResetAt       time.Time `json:"reset_at" yaml:"reset_at"`
+}
+
+type ReportError struct {
+	Fetch   string `json:"fetch" yaml:"fetch"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// NewReport assembles the report for an analysis run from the given
+// sections
+func NewReport(ctx context.Context, sections []Section, r *analysis.Result, client *github.Client) Report {
+	repo := r.Repo
+	report := Report{
+		SchemaVersion: ReportSchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		Repository: ReportRepository{
+			FullName:      repo.FullName,
+			Description:   repo.Description,
+			URL:           repo.HTMLURL,
+			DefaultBranch: repo.DefaultBranch,
+			Language:      repo.Language,
+			Stars:         repo.Stars,
+			Forks:         repo.Forks,
+			Watchers:      repo.WatchersCount,
+			OpenIssues:    repo.OpenIssues,
+			Fork:          repo.Fork,
+			Archived:      repo.Archived,
+			CreatedAt:     repo.CreatedAt,
+			PushedAt:      repo.PushedAt,
+		},
+		Errors: []ReportError{},
+	}
+
+	for _, s := range sections {
+		if s.fill != nil {
+			s.fill(ctx, &report, r, client)
+		}
+	}
+	for _, f := range analysis.AllFetches {
+		if err, ok := r.Errors[f]; ok {
+			report.Errors = append(report.Errors, ReportError{Fetch: string(f), Message: err.Error()})
+		}
+	}
+	return report
+}
+
+func reportHealth(r *analysis.Result) *ReportHealth {
+	breakdown := []ReportHealthCheck{}
+	for _, c := range r.Health.Checks {
+		breakdown = append(breakdown, ReportHealthCheck{
+			Rule:      c.Rule,
+			Name:      c.Name,
+			Points:    c.Points,
+			MaxPoints: c.MaxPoints,
+			Skipped:   c.Skipped,
+			Detail:    c.Detail,
+		})
+	}
+	return &ReportHealth{
+		Score:     r.HealthScore,
+		Label:     healthLabel(r.HealthScore),
+		Profile:   r.Health.Profile,
+		Breakdown: breakdown,
+		Issues: ReportIssueHealth{
+			Score:                   r.IssueHealth.Score,
+			Grade:                   r.IssueHealth.Grade,
+			Verdict:                 r.IssueHealth.Verdict,
+			Total:                   r.IssueHealth.Total,
+			Open:                    r.IssueHealth.Open,
+			Closed:                  r.IssueHealth.Closed,
+			Stale:                   r.IssueHealth.Stale,
+			MedianCloseDays:         days(r.IssueHealth.MedianTimeToClose),
+			MedianFirstResponseDays: days(r.IssueHealth.MedianFirstResponse),
+			ResponseKnown:           r.IssueHealth.ResponseKnown,
+			Responded:               r.IssueHealth.Responded,
+			ResponsePartial:         r.IssueHealth.ResponsePartial,
+		},
+		PullRequests: ReportPullsHealth{
+			Score:              r.PRHealth.Score,
+			Grade:              r.PRHealth.Grade,
+			Verdict:            r.PRHealth.Verdict,
+			Total:              r.PRHealth.Total,
+			Open:               r.PRHealth.Open,
+			Merged:             r.PRHealth.Merged,
+			MergeRate:          r.PRHealth.MergeRate,
+			MedianMergeDays:    days(r.PRHealth.MedianTimeToMerge),
+			ReviewCoverage:     r.PRHealth.ReviewCoverage,
+			ExternalAcceptance: r.PRHealth.ExternalAcceptance,
+			Sampled:            r.PRHealth.Sampled,
+			SampledMerged:      r.PRHealth.SampledMerged,
+			ReviewPartial:      r.PRHealth.ReviewPartial,
+		},
+	}
+}
+
+func reportMaturity(m analyzer.Maturity) *ReportMaturity {
+	signals := []ReportMaturitySignal{}
+	for _, sig := range m.Signals {
+		signals = append(signals, ReportMaturitySignal{
+			Signal: sig.Signal,
+			Name:   sig.Name,
+			Score:  sig.Score,
+			Weight: sig.Weight,
+			Known:  sig.Known,
+			Detail: sig.Detail,
+		})
+	}
+	return &ReportMaturity{Score: m.Score, Level: m.Level, Confidence: m.Confidence, Signals: signals}
+}
+
+func reportBusFactor(tf analyzer.TruckFactor) *ReportBusFactor {
+	bus := &ReportBusFactor{Value: tf.Value, Risk: tf.Risk, Mode: tf.Mode, KeyPeople: []ReportKeyPerson{}}
+	for _, p := range tf.KeyPeople {
+		bus.KeyPeople = append(bus.KeyPeople, ReportKeyPerson{Login: p.Login, Files: p.Files, Share: p.Share})
+	}
+	return bus
+}
+
+func reportRecruiter(s analyzer.RecruiterSummary) *ReportRecruiter {
+	return &ReportRecruiter{
+		ActivityLevel:   s.ActivityLevel,
+		CommitsLastYear: s.CommitsLastYear,
+		Contributors:    s.Contributors,
+		IssueHealth:     s.IssueHealth,
+		PRHealth:        s.PRHealth,
+	}
+}
+
+// NewReportAPIStatus reads the rate limit budget, preferring the one seen
+// on the last response over spending a request. It returns nil when the
+// budget cannot be fetched.
+func NewReportAPIStatus(ctx context.Context, client *github.Client) *ReportAPIStatus {
+	rateLimit, ok := client.RateBudget()
+	if !ok {
+		var err error
+		if rateLimit, err = client.GetRateLimit(ctx); err != nil {
+			return nil
+		}
+	}
+	return &ReportAPIStatus{
+		Authenticated: client.Authenticated(),
+		Limit:         rateLimit.Resources.Core.Limit,
+		Remaining:     rateLimit.Resources.Core.Remaining,
+		ResetAt:       rateLimit.ResetTime().UTC(),
+	}
+}
+
+// reportLanguages lists languages by size, largest first
+func reportLanguages(langs map[string]int) []ReportLanguage {
+	total := 0
+	for _, size := range langs {
+		total += size
+	}
+
+	list := []ReportLanguage{}
+	for name, size := range langs {
+		list = append(list, ReportLanguage{Name: name, Bytes: size, Percent: float64(size) / float64(total) * 100})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Bytes != list[j].Bytes {
+			return list[i].Bytes > list[j].Bytes
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func reportActivity(commits []github.Commit) *ReportActivity {
+	perDay := analyzer.CommitsPerDay(commits)
+	activity := &ReportActivity{Commits: len(commits), ActiveDays: len(perDay), Daily: []ReportDay{}}
+	for date, count := range perDay {
+		activity.Daily = append(activity.Daily, ReportDay{Date: date, Commits: count})
+	}
+	sort.Slice(activity.Daily, func(i, j int) bool {
+		return activity.Daily[i].Date < activity.Daily[j].Date
+	})
+	return activity
+}
+
+// days converts a duration to fractional days, rounded to two places
+func days(d time.Duration) float64 {
+	return float64(int(d.Hours()/24*100+0.5)) / 100
+}
@@ -0,0 +1,208 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// SectionAll selects every section
+const SectionAll = "all"
+
+// Section is one part of the analyze output. Needs lists the fetches its
+// data comes from, so a run only spends requests on the sections asked for;
+// the repository itself is always fetched.
+type Section struct {
+	Name        string
+	Description string
+	Needs       []analysis.Fetch
+	// scoresHealth marks sections showing the health score, which also
+	// need whatever the health profile's rules read
+	scoresHealth bool
+
+	// print renders the section as text; fill adds it to a Report
+	print func(ctx context.Context, r *analysis.Result, client *github.Client)
+	fill  func(ctx context.Context, report *Report, r *analysis.Result, client *github.Client)
+}
+
+var (
+	issueNeeds = []analysis.Fetch{analysis.FetchIssues, analysis.FetchComments}
+	pullNeeds  = []analysis.Fetch{analysis.FetchPulls, analysis.FetchPullDetails, analysis.FetchReviewComments}
+	// The bus factor comes from file authorship, falling back to
+	// contributor counts
+	busNeeds = []analysis.Fetch{analysis.FetchCommits, analysis.FetchContributors, analysis.FetchTree, analysis.FetchCommitFiles}
+	// Maturity reads commit authors, releases (or tags), issues and the
+	// community files in the tree
+	maturityNeeds = []analysis.Fetch{analysis.FetchCommits, analysis.FetchReleases, analysis.FetchTags, analysis.FetchIssues, analysis.FetchTree}
+)
+
+// Sections is the registry of analyze output sections, in print order
+var Sections = []Section{
+	{
+		Name:        "repo",
+		Description: "stars, forks and open issues",
+		print: func(_ context.Context, r *analysis.Result, _ *github.Client) {
+			PrintRepo(r.Repo)
+		},
+	},
+	{
+		Name:        "langs",
+		Description: "language breakdown",
+		Needs:       []analysis.Fetch{analysis.FetchLanguages},
+		print: func(_ context.Context, r *analysis.Result, _ *github.Client) {
+			PrintLanguages(r.Languages)
+		},
+		fill: func(_ context.Context, report *Report, r *analysis.Result, _ *github.Client) {
+			report.Languages = reportLanguages(r.Languages)
+		},
+	},
+	{
+		Name:        "activity",
+		Description: "commits per day",
+		Needs:       []analysis.Fetch{analysis.FetchCommits},
+		print: func(_ context.Context, r *analysis.Result, _ *github.Client) {
+			PrintCommitActivity(analyzer.CommitsPerDay(r.Commits), 14)
+		},
+		fill: func(_ context.Context, report *Report, r *analysis.Result, _ *github.Client) {
+			report.Activity = reportActivity(r.Commits)
+		},
+	},
+	{
+		Name:         "health",
+		Description:  "health score with issue and pull request health",
+		Needs:        analysis.ConcatFetches(issueNeeds, pullNeeds),
+		scoresHealth: true,
+		print: func(_ context.Context, r *analysis.Result, _ *github.Client) {
+			PrintHealth(r.HealthScore)
+			PrintHealthBreakdown(r.Health)
+			fmt.Println("🐛 Issue Health:", r.IssueHealth.Summary())
+			fmt.Println("🔀 PR Health:", r.PRHealth.Summary())
+		},
+		fill: func(_ context.Context, report *Report, r *analysis.Result, _ *github.Client) {
+			report.Health = reportHealth(r)
+		},
+	},
+	{
+		Name:        "bus",
+		Description: "bus (truck) factor and key people",
+		Needs:       busNeeds,
+		print: func(_ context.Context, r *analysis.Result, _ *github.Client) {
+			PrintTruckFactor(r.TruckFactor)
+		},
+		fill: func(_ context.Context, report *Report, r *analysis.Result, _ *github.Client) {
+			report.BusFactor = reportBusFactor(r.TruckFactor)
+		},
+	},
+	{
+		Name:        "maturity",
+		Description: "maturity score, level and signals",
+		Needs:       maturityNeeds,
+		print: func(_ context.Context, r *analysis.Result, _ *github.Client) {
+			PrintMaturity(r.Maturity)
+		},
+		fill: func(_ context.Context, report *Report, r *analysis.Result, _ *github.Client) {
+			report.Maturity = reportMaturity(r.Maturity)
+		},
+	},
+	{
+		Name:        "api",
+		Description: "GitHub API rate limit",
+		print: func(ctx context.Context, _ *analysis.Result, client *github.Client) {
+			PrintGitHubAPIStatus(ctx, client)
+		},
+		fill: func(ctx context.Context, report *Report, _ *analysis.Result, client *github.Client) {
+			report.APIStatus = NewReportAPIStatus(ctx, client)
+		},
+	},
+	{
+		Name:        "recruiter",
+		Description: "one-screen summary for recruiters",
+		Needs:       analysis.ConcatFetches(maturityNeeds, busNeeds, issueNeeds, pullNeeds),
+		print: func(_ context.Context, r *analysis.Result, _ *github.Client) {
+			PrintRecruiterSummary(recruiterSummary(r))
+		},
+		fill: func(_ context.Context, report *Report, r *analysis.Result, _ *github.Client) {
+			report.Recruiter = reportRecruiter(recruiterSummary(r))
+		},
+	},
+}
+
+// SectionNames lists the registered section names
+func SectionNames() []string {
+	names := make([]string, len(Sections))
+	for i, s := range Sections {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// SelectSections looks up sections by name, keeping registry order. "all"
+// selects every section.
+func SelectSections(names []string) ([]Section, error) {
+	want := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == SectionAll {
+			return Sections, nil
+		}
+		want[name] = true
+	}
+
+	var selected []Section
+	for _, s := range Sections {
+		if want[s.Name] {
+			selected = append(selected, s)
+			delete(want, s.Name)
+		}
+	}
+	for name := range want {
+		return nil, fmt.Errorf("unknown section %q (available: %s, %s)", name, strings.Join(SectionNames(), ", "), SectionAll)
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("no sections selected")
+	}
+	return selected, nil
+}
+
+// SectionNeeds is every fetch the sections need when health is scored with
+// profile, for analysis.Options.Only
+func SectionNeeds(sections []Section, profile analyzer.HealthProfile) []analysis.Fetch {
+	needs := []analysis.Fetch{}
+	for _, s := range sections {
+		needs = analysis.ConcatFetches(needs, s.Needs)
+		if s.scoresHealth {
+			needs = analysis.ConcatFetches(needs, analysis.HealthFetches(profile))
+		}
+	}
+	return needs
+}
+
+// RenderText prints the sections in order
+func RenderText(ctx context.Context, sections []Section, r *analysis.Result, client *github.Client) {
+	for _, s := range sections {
+		s.print(ctx, r, client)
+	}
+}
+
+// recruiterSummary condenses a result for the recruiter section
+func recruiterSummary(r *analysis.Result) analyzer.RecruiterSummary {
+	summary := analyzer.BuildRecruiterSummary(
+		r.Repo.FullName,
+		r.Repo.Stars,
+		r.Repo.Forks,
+		len(r.Commits),
+		len(r.Contributors),
+		r.MaturityScore,
+		r.MaturityLevel,
+		r.BusFactor,
+		r.BusRisk,
+	)
+	summary.IssueHealth = r.IssueHealth.Summary()
+	summary.PRHealth = r.PRHealth.Summary()
+	return summary
+}
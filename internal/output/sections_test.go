@@ -0,0 +1,110 @@
+package output
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+)
+
+func sectionNames(sections []Section) string {
+	names := make([]string, len(sections))
+	for i, s := range sections {
+		names[i] = s.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSelectSections(t *testing.T) {
+	all := strings.Join(SectionNames(), ",")
+	tests := []struct {
+		name    string
+		names   []string
+		want    string
+		wantErr string
+	}{
+		{"default", []string{SectionAll}, all, ""},
+		{"all among others", []string{"bus", " ALL "}, all, ""},
+		{"registry order", []string{"maturity", "Repo", " langs"}, "repo,langs,maturity", ""},
+		{"repeated", []string{"bus", "bus"}, "bus", ""},
+		{"unknown", []string{"repo", "vibes"}, "", `unknown section "vibes"`},
+		{"none", nil, "", "no sections selected"},
+		{"blank", []string{""}, "", `unknown section ""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectSections(tt.names)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names := sectionNames(got); names != tt.want {
+				t.Errorf("sections = %s, want %s", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionNeeds(t *testing.T) {
+	f := func(fetches ...analysis.Fetch) []analysis.Fetch { return fetches }
+	defaultProfile, _ := analyzer.HealthPreset(analyzer.DefaultHealthProfile)
+	starsOnly := analyzer.HealthProfile{Rules: []analyzer.HealthRule{{Rule: "stars", Weight: 1}}}
+	health := f(analysis.FetchIssues, analysis.FetchComments, analysis.FetchPulls, analysis.FetchPullDetails, analysis.FetchReviewComments)
+	bus := f(analysis.FetchCommits, analysis.FetchContributors, analysis.FetchTree, analysis.FetchCommitFiles)
+
+	tests := []struct {
+		section string
+		profile analyzer.HealthProfile
+		want    []analysis.Fetch
+	}{
+		{"repo", defaultProfile, f()},
+		{"api", defaultProfile, f()},
+		{"langs", defaultProfile, f(analysis.FetchLanguages)},
+		{"activity", defaultProfile, f(analysis.FetchCommits)},
+		{"bus", defaultProfile, bus},
+		{"maturity", defaultProfile, f(analysis.FetchCommits, analysis.FetchReleases, analysis.FetchTags, analysis.FetchIssues, analysis.FetchTree)},
+		{"recruiter", defaultProfile, f(analysis.FetchCommits, analysis.FetchReleases, analysis.FetchTags, analysis.FetchIssues, analysis.FetchTree,
+			analysis.FetchContributors, analysis.FetchCommitFiles, analysis.FetchComments, analysis.FetchPulls, analysis.FetchPullDetails, analysis.FetchReviewComments)},
+		// Health adds what the profile's rules read
+		{"health", starsOnly, health},
+		{"health", defaultProfile, append(f(analysis.FetchTree, analysis.FetchCommits), health...)},
+		{"health", analyzer.HealthProfile{Rules: []analyzer.HealthRule{{Rule: "security", Weight: 1}}},
+			append(f(analysis.FetchTree, analysis.FetchCommits, analysis.FetchBranch, analysis.FetchWorkflows), health...)},
+		// An empty profile scores with the default one
+		{"health", analyzer.HealthProfile{}, append(f(analysis.FetchTree, analysis.FetchCommits), health...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.section, func(t *testing.T) {
+			sections, err := SelectSections([]string{tt.section})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := SectionNeeds(sections, tt.profile)
+			// nil would ask analysis.Options.Only for every fetch
+			if got == nil {
+				t.Fatal("SectionNeeds = nil")
+			}
+			slices.Sort(got)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("needs = %v, want %v", got, want)
+			}
+		})
+	}
+
+	// Needs of several sections are merged in registry order without repeats
+	sections, _ := SelectSections([]string{"activity", "bus", "langs"})
+	got := SectionNeeds(sections, defaultProfile)
+	want := f(analysis.FetchLanguages, analysis.FetchCommits, analysis.FetchContributors, analysis.FetchTree, analysis.FetchCommitFiles)
+	if !slices.Equal(got, want) {
+		t.Errorf("needs = %v, want %v", got, want)
+	}
+}
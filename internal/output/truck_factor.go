@@ -0,0 +1,42 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+)
+
+// PrintTruckFactor lists the key people and per-directory owners
+func PrintTruckFactor(tf analyzer.TruckFactor) {
+	fmt.Println(SectionStyle.Render("\n🚌 Truck Factor"))
+	fmt.Printf("Truck factor: %d (%s)\n", tf.Value, tf.Risk)
+
+	if tf.Mode == analyzer.TruckModeHeuristic {
+		fmt.Println("Estimated from the top contributor's share of commits")
+		for _, p := range tf.KeyPeople {
+			fmt.Printf("  🔑 %-20s %.0f%% of commits\n", p.Login, p.Share*100)
+		}
+		return
+	}
+
+	fmt.Printf("From authorship of %d files in %d sampled commits (threshold %.0f%%)\n",
+		tf.FilesAnalyzed, tf.CommitsSampled, tf.Threshold*100)
+	for _, p := range tf.KeyPeople {
+		fmt.Printf("  🔑 %-20s authors %d files (%.0f%%)\n", p.Login, p.Files, p.Share*100)
+	}
+
+	if len(tf.Directories) == 0 {
+		return
+	}
+	fmt.Println("\nDirectory ownership:")
+	for i, d := range tf.Directories {
+		if i == 10 {
+			break
+		}
+		line := fmt.Sprintf("  %-20s %4d files  %-18s %.0f%%", d.Dir, d.Files, d.Owner, d.Share*100)
+		if d.Orphaned {
+			line += WarningStyle.Render("  only key people")
+		}
+		fmt.Println(line)
+	}
+}
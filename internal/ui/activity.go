@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxActivityCommits caps how many commits' file lists are read when
+// working out which paths changed
+const maxActivityCommits = 100
+
+// activityReserve is the rate budget left untouched by activity loading
+const activityReserve = 50
+
+// activityFetchTimeout bounds loading path activity, one request per commit
+const activityFetchTimeout = 2 * time.Minute
+
+// fileActivity is what the commits in a window did to one path
+type fileActivity struct {
+	LastChanged time.Time
+	Commits     int
+}
+
+// pathActivity maps paths to the commits that touched them since a point
+// in time. Directories are included, keyed by their path with "" for the
+// root, counting each commit once however many files below it changed.
+type pathActivity struct {
+	Since   time.Time
+	Commits int  // commits whose file lists were read
+	Partial bool // the window held more commits than were read
+	Failed  bool // reading stopped on an error; fetched again when next needed
+	MaxFile int  // most commits touching any one file
+	Paths   map[string]*fileActivity
+}
+
+// activityLoadedMsg delivers path activity for a window
+type activityLoadedMsg struct {
+	activity *pathActivity
+	err      error
+}
+
+// covers reports whether the activity reaches back to since
+func (a *pathActivity) covers(since time.Time) bool {
+	return a != nil && !a.Failed && !a.Since.After(since)
+}
+
+// changedSince reports whether any commit touched path after cutoff. For
+// directories, a change to anything below counts.
+func (a *pathActivity) changedSince(path string, cutoff time.Time) bool {
+	f := a.Paths[path]
+	return f != nil && f.LastChanged.After(cutoff)
+}
+
+// add records one commit's file list against the files and every directory
+// above them
+func (a *pathActivity) add(commit github.Commit) {
+	a.Commits++
+	date := commit.Commit.Author.Date
+
+	dirs := map[string]bool{}
+	for _, file := range commit.Files {
+		if f := a.record(file.Filename, date); f.Commits > a.MaxFile {
+			a.MaxFile = f.Commits
+		}
+		for dir := file.Filename; dir != ""; {
+			dir = dir[:max(strings.LastIndex(dir, "/"), 0)]
+			dirs[dir] = true
+		}
+	}
+	for dir := range dirs {
+		a.record(dir, date)
+	}
+}
+
+// record counts one commit against path
+func (a *pathActivity) record(path string, date time.Time) *fileActivity {
+	f := a.Paths[path]
+	if f == nil {
+		f = &fileActivity{}
+		a.Paths[path] = f
+	}
+	f.Commits++
+	if date.After(f.LastChanged) {
+		f.LastChanged = date
+	}
+	return f
+}
+
+// loadActivity lists the commits since a point in time and reads their
+// file lists, reusing the commits the analysis already fetched in detail.
+// Merges are skipped since their files repeat the merged commits.
+func loadActivity(client *github.Client, target analysis.Target, since time.Time, known []github.Commit) tea.Cmd {
+	detailed := map[string]github.Commit{}
+	for _, commit := range known {
+		detailed[commit.SHA] = commit
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), activityFetchTimeout)
+		defer cancel()
+
+		activity := &pathActivity{Since: since, Paths: map[string]*fileActivity{}}
+		commits, err := client.ListCommits(ctx, target.Owner, target.Name, github.CommitOptions{
+			Since:    since,
+			MaxCount: maxActivityCommits + 1,
+		})
+		if err != nil {
+			return activityLoadedMsg{err: err}
+		}
+		if len(commits) > maxActivityCommits {
+			commits, activity.Partial = commits[:maxActivityCommits], true
+		}
+
+		for _, commit := range commits {
+			if commit.IsMerge() {
+				continue
+			}
+			if d, ok := detailed[commit.SHA]; ok {
+				activity.add(d)
+				continue
+			}
+			if budget, ok := client.RateBudget(); ok && budget.Resources.Core.Remaining < activityReserve {
+				activity.Partial = true
+				break
+			}
+			d, err := client.GetCommit(ctx, target.Owner, target.Name, commit.SHA)
+			if err != nil {
+				activity.Partial, activity.Failed = true, true
+				return activityLoadedMsg{activity: activity, err: err}
+			}
+			activity.add(*d)
+		}
+		return activityLoadedMsg{activity: activity}
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestActivityCovers(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		activity *pathActivity
+		since    time.Time
+		want     bool
+	}{
+		{"nothing loaded", nil, since, false},
+		{"same window", &pathActivity{Since: since}, since, true},
+		{"shorter window", &pathActivity{Since: since}, since.AddDate(0, 1, 0), true},
+		{"longer window", &pathActivity{Since: since}, since.AddDate(0, -1, 0), false},
+		{"capped window", &pathActivity{Since: since, Partial: true}, since, true},
+		{"failed window", &pathActivity{Since: since, Partial: true, Failed: true}, since, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.activity.covers(tt.since); got != tt.want {
+				t.Errorf("covers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivityAdd(t *testing.T) {
+	a := &pathActivity{Paths: map[string]*fileActivity{}}
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	commit := func(date time.Time, files ...string) github.Commit {
+		var c github.Commit
+		c.Commit.Author.Date = date
+		for _, f := range files {
+			c.Files = append(c.Files, github.CommitFile{Filename: f})
+		}
+		return c
+	}
+	a.add(commit(day, "cmd/a.go", "cmd/b.go"))
+	a.add(commit(day.AddDate(0, 0, 1), "cmd/a.go", "README.md"))
+
+	want := map[string]int{"cmd/a.go": 2, "cmd/b.go": 1, "README.md": 1, "cmd": 2, "": 2}
+	for path, n := range want {
+		if f := a.Paths[path]; f == nil || f.Commits != n {
+			t.Errorf("%q counted %v, want %d commits", path, f, n)
+		}
+	}
+	if a.Commits != 2 || a.MaxFile != 2 {
+		t.Errorf("Commits, MaxFile = %d, %d, want 2, 2", a.Commits, a.MaxFile)
+	}
+	if !a.changedSince("cmd", day) || a.changedSince("cmd/b.go", day) {
+		t.Error("changedSince does not follow the latest change below each path")
+	}
+}
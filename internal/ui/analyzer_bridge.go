@@ -138,10 +138,13 @@ func (b *AnalyzerDataBridge) getHealthColor() string {
 	return "red"
 }
 
+// getRiskColor follows the risk label, whose numeric cut-offs depend on
+// whether the truck factor or the fast heuristic produced it
 func (b *AnalyzerDataBridge) getRiskColor() string {
-	if b.busFactor >= 7 {
+	switch b.busRisk {
+	case "Low Risk":
 		return "green"
-	} else if b.busFactor >= 4 {
+	case "Medium Risk":
 		return "yellow"
 	}
 	return "red"
@@ -282,11 +285,12 @@ func (b *AnalyzerDataBridge) GenerateSummary() string {
 	}
 
 	// Bus factor assessment
-	if b.busFactor <= 2 {
+	switch b.busRisk {
+	case "High Risk":
 		summary += "🚌 WARNING: High dependency on few contributors.\n"
-	} else if b.busFactor <= 4 {
+	case "Medium Risk":
 		summary += "⚠️ Some concentration of key contributors.\n"
-	} else {
+	case "Low Risk":
 		summary += "✅ Good distribution of contributor responsibility.\n"
 	}
 
@@ -319,7 +323,7 @@ func (b *AnalyzerDataBridge) GenerateRecommendations() []string {
 	}
 
 	// Bus factor recommendations
-	if b.busFactor <= 2 {
+	if b.busRisk == "High Risk" {
 		recommendations = append(recommendations, "Recruit and onboard more contributors")
 		recommendations = append(recommendations, "Document critical processes and architecture")
 	}
@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
 	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 	"github.com/agnivo988/Repo-lyzer/internal/github"
 	"github.com/charmbracelet/bubbles/help"
@@ -32,23 +35,38 @@ type MainModel struct {
 	state         sessionState
 	menu          MenuModel
 	input         string // Repository input
-	compareInput1 string // First repo for comparison
-	compareInput2 string // Second repo for comparison
-	compareStep   int    // 0 = entering first repo, 1 = entering second repo
+	compareInput  string   // Repo being typed for comparison
+	compareList   []string // Repos entered for comparison so far
 	spinner       spinner.Model
 	dashboard     DashboardModel
 	tree          TreeModel
 	help          help.Model
-	progress      *ProgressTracker
+	progress      []*ProgressTracker // One per repository being analyzed
+	progressCh    <-chan analysis.Event
 	err           error
 	windowWidth   int
 	windowHeight  int
 	analysisType  string // quick, detailed, custom
 	appSettings    tea.LogOptionsSetter
 	compareResult *CompareResult // Holds comparison data
+	weights       analysis.Weights   // Ranking weights for comparisons
+	window        analysis.Window    // Commit window for comparisons
+	healthProfile analyzer.HealthProfile
+	client        *github.Client // Shared GitHub API client
+	cancel        context.CancelFunc // Cancels the in-flight analysis, if any
 }
 
-func NewMainModel() MainModel {
+// Options configures the TUI.
+type Options struct {
+	// CompareWeights sets how comparisons rank repositories; nil weighs
+	// every dimension equally.
+	CompareWeights analysis.Weights
+	// HealthProfile scores health; a profile without rules means the
+	// default preset.
+	HealthProfile analyzer.HealthProfile
+}
+
+func NewMainModel(client *github.Client, opts Options) MainModel {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
@@ -58,11 +76,78 @@ func NewMainModel() MainModel {
 		menu:         NewMenuModel(),
 		spinner:      s,
 		dashboard:    NewDashboardModel(),
-		tree:         NewTreeModel(nil),
-		appSettings:  nil, 
+		tree:         NewTreeModel(nil, client),
+		appSettings:  nil,
+		client:       client,
+		weights:      opts.CompareWeights,
+		window:       analysis.DefaultWindow,
+		healthProfile: opts.HealthProfile,
+	}
+}
+
+
+// newRequestContext cancels any analysis still running and returns a fresh
+// context for the next one.
+func (m *MainModel) newRequestContext() context.Context {
+	m.cancelPending()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	return ctx
+}
+
+// cancelPending aborts the in-flight analysis, if any.
+func (m *MainModel) cancelPending() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
 	}
 }
 
+// startAnalysis launches the analysis of repoName along with the
+// subscription that streams its progress into the model.
+func (m *MainModel) startAnalysis(repoName string) tea.Cmd {
+	events, subscribe := m.startProgress(analysis.AllFetches, repoName)
+	return tea.Batch(m.analyzeRepo(m.newRequestContext(), repoName, events), subscribe)
+}
+
+// startCompare is startAnalysis for a comparison.
+func (m *MainModel) startCompare(repoNames []string) tea.Cmd {
+	events, subscribe := m.startProgress(analysis.CompareOptions(m.window, m.healthProfile).Fetches(), repoNames...)
+	return tea.Batch(m.compareRepos(m.newRequestContext(), repoNames, events), subscribe)
+}
+
+// startProgress resets the trackers, one stage per fetch, and creates the
+// channel the pipeline reports on. Events from any earlier channel are
+// ignored from now on.
+func (m *MainModel) startProgress(fetches []analysis.Fetch, repoNames ...string) (chan analysis.Event, tea.Cmd) {
+	events := make(chan analysis.Event, 32)
+	m.progressCh = events
+	m.progress = nil
+	for _, name := range repoNames {
+		if target, err := analysis.ParseTarget(name); err == nil {
+			name = target.String()
+		}
+		m.progress = append(m.progress, NewProgressTracker(name, fetches))
+	}
+	return events, waitForProgress(events)
+}
+
+// stopProgress drops the trackers once a run has ended.
+func (m *MainModel) stopProgress() {
+	m.progress = nil
+	m.progressCh = nil
+}
+
+// sendProgress forwards pipeline events to events until ctx is cancelled,
+// so an abandoned run never blocks on a channel nobody reads.
+func sendProgress(ctx context.Context, events chan<- analysis.Event) func(analysis.Event) {
+	return func(ev analysis.Event) {
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+		}
+	}
+}
 
 func (m MainModel) Init() tea.Cmd {
 	return m.spinner.Tick
@@ -85,6 +170,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
+			m.cancelPending()
 			return m, tea.Quit
 		}
 		// Global shortcuts
@@ -92,25 +178,36 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+	case ProgressUpdateMsg:
+		// Only the current run's events count; stale ones end their subscription
+		if msg.events == m.progressCh {
+			for _, tracker := range m.progress {
+				if tracker.Target == msg.Event.Target.String() {
+					tracker.Apply(msg.Event)
+				}
+			}
+			cmds = append(cmds, waitForProgress(msg.events))
+		}
+
 	case string:
 		if msg == "switch_to_tree" {
 			m.state = stateTree
 			// Update tree with current analysis data
 			if m.dashboard.data.Repo != nil {
-				m.tree = NewTreeModel(&m.dashboard.data)
+				m.tree = NewTreeModel(&m.dashboard.data, m.client)
 				// Initialize tree with current window size
 				var cmd tea.Cmd
 				var tm tea.Model
 				tm, cmd = m.tree.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 				m.tree = tm.(TreeModel)
-				cmds = append(cmds, cmd)
+				cmds = append(cmds, cmd, m.tree.Init())
 			}
 		}
 		if msg == "refresh_data" {
 			// Re-analyze the current repo
 			if m.dashboard.data.Repo != nil {
 				m.state = stateLoading
-				cmds = append(cmds, m.analyzeRepo(m.dashboard.data.Repo.FullName))
+				cmds = append(cmds, m.startAnalysis(m.dashboard.data.Repo.FullName))
 			}
 		}
 	}
@@ -126,9 +223,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.menu.Done = false // Reset for back navigation
 		} else if m.menu.SelectedOption == 1 && m.menu.Done { // Compare
 			m.state = stateCompareInput
-			m.compareStep = 0
-			m.compareInput1 = ""
-			m.compareInput2 = ""
+			m.compareInput = ""
+			m.compareList = nil
 			m.menu.Done = false
 		} else if m.menu.SelectedOption == 2 && m.menu.Done { // Exit
 			return m, tea.Quit
@@ -141,7 +237,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case tea.KeyEnter:
 				if m.input != "" {
 					m.state = stateLoading
-					cmds = append(cmds, m.analyzeRepo(m.input))
+					cmds = append(cmds, m.startAnalysis(m.input))
 				}
 			case tea.KeyBackspace:
 				if len(m.input) > 0 {
@@ -174,59 +270,47 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyMsg:
 			switch msg.Type {
 			case tea.KeyEnter:
-				if m.compareStep == 0 && m.compareInput1 != "" {
-					// Move to second repo input
-					m.compareStep = 1
-				} else if m.compareStep == 1 && m.compareInput2 != "" {
-					// Both repos entered, start comparison
+				if m.compareInput != "" {
+					// Add the repo to the list
+					if err := m.addCompareRepo(m.compareInput); err != nil {
+						m.err = err
+					} else {
+						m.compareInput = ""
+						m.err = nil
+					}
+				} else if len(m.compareList) >= 2 {
+					// Empty line with enough repos: start comparison
 					m.state = stateCompareLoading
-					cmds = append(cmds, m.compareRepos(m.compareInput1, m.compareInput2))
+					m.err = nil
+					cmds = append(cmds, m.startCompare(m.compareList))
 				}
 			case tea.KeyBackspace:
-				if m.compareStep == 0 && len(m.compareInput1) > 0 {
-					m.compareInput1 = m.compareInput1[:len(m.compareInput1)-1]
-				} else if m.compareStep == 1 && len(m.compareInput2) > 0 {
-					m.compareInput2 = m.compareInput2[:len(m.compareInput2)-1]
+				if len(m.compareInput) > 0 {
+					m.compareInput = m.compareInput[:len(m.compareInput)-1]
 				}
 			case tea.KeyRunes:
-				if m.compareStep == 0 {
-					m.compareInput1 += string(msg.Runes)
-				} else {
-					m.compareInput2 += string(msg.Runes)
-				}
+				m.compareInput += string(msg.Runes)
 			case tea.KeyEsc:
-				if m.compareStep == 1 {
-					// Go back to first repo input
-					m.compareStep = 0
+				if len(m.compareList) > 0 {
+					// Drop the last repo added
+					m.compareList = m.compareList[:len(m.compareList)-1]
 				} else {
 					m.state = stateMenu
 					m.menu.Done = false
-					m.compareInput1 = ""
-					m.compareInput2 = ""
+					m.compareInput = ""
 				}
+				m.err = nil
+			case tea.KeyTab:
+				m.window = m.window.Next()
 			case tea.KeyCtrlU:
-				// Clear current input
-				if m.compareStep == 0 {
-					m.compareInput1 = ""
-				} else {
-					m.compareInput2 = ""
-				}
+				m.compareInput = "" // Clear current input
 			case tea.KeyCtrlW:
 				// Delete word backward
-				if m.compareStep == 0 {
-					m.compareInput1 = strings.TrimRight(m.compareInput1, " ")
-					if idx := strings.LastIndex(m.compareInput1, " "); idx >= 0 {
-						m.compareInput1 = m.compareInput1[:idx+1]
-					} else {
-						m.compareInput1 = ""
-					}
+				m.compareInput = strings.TrimRight(m.compareInput, " ")
+				if idx := strings.LastIndex(m.compareInput, " "); idx >= 0 {
+					m.compareInput = m.compareInput[:idx+1]
 				} else {
-					m.compareInput2 = strings.TrimRight(m.compareInput2, " ")
-					if idx := strings.LastIndex(m.compareInput2, " "); idx >= 0 {
-						m.compareInput2 = m.compareInput2[:idx+1]
-					} else {
-						m.compareInput2 = ""
-					}
+					m.compareInput = ""
 				}
 			}
 		}
@@ -237,18 +321,25 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		switch msg := msg.(type) {
 		case CompareResult:
+			m.cancelPending()
+			m.stopProgress()
 			m.compareResult = &msg
 			m.state = stateCompareResult
 			m.err = nil
 		case error:
+			// A cancelled run reporting back late is not an error
+			if errors.Is(msg, context.Canceled) {
+				break
+			}
+			m.cancelPending()
+			m.stopProgress()
 			m.err = msg
 			m.state = stateCompareInput
-			m.compareStep = 0
 		case tea.KeyMsg:
 			if msg.String() == "esc" {
-				m.state = stateMenu
-				m.compareInput1 = ""
-				m.compareInput2 = ""
+				m.cancelPending()
+				m.stopProgress()
+				m.state = stateCompareInput
 				m.err = nil
 			}
 		}
@@ -260,8 +351,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "q", "esc":
 				m.state = stateMenu
 				m.compareResult = nil
-				m.compareInput1 = ""
-				m.compareInput2 = ""
+				m.compareInput = ""
+				m.compareList = nil
 			}
 		}
 
@@ -269,15 +360,27 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		cmds = append(cmds, cmd)
 
-		if result, ok := msg.(AnalysisResult); ok {
-			m.dashboard.SetData(result)
+		switch msg := msg.(type) {
+		case AnalysisResult:
+			m.cancelPending()
+			m.dashboard.SetData(msg)
 			m.state = stateDashboard
-			m.progress = nil
-		}
-		if err, ok := msg.(error); ok {
-			m.err = err
+			m.stopProgress()
+		case error:
+			// A cancelled run reporting back late is not an error
+			if errors.Is(msg, context.Canceled) {
+				break
+			}
+			m.cancelPending()
+			m.err = msg
 			m.state = stateInput // Go back to input on error
-			m.progress = nil
+			m.stopProgress()
+		case tea.KeyMsg:
+			if msg.String() == "esc" {
+				m.cancelPending()
+				m.state = stateInput
+				m.stopProgress()
+			}
 		}
 
 	case stateDashboard:
@@ -321,23 +424,9 @@ func (m MainModel) View() string {
 
 		statusView := fmt.Sprintf("%s %s...", m.spinner.View(), loadMsg)
 
-		// Show progress stages if available
-		if m.progress != nil {
-			stages := m.progress.GetAllStages()
-			statusView += "\n\n"
-			for _, stage := range stages {
-				prefix := "⏳ "
-				if stage.IsComplete {
-					prefix = "✅ "
-				} else if stage.IsActive {
-					prefix = "⚙️  "
-				}
-				statusView += prefix + stage.Name + "\n"
-			}
-
-			// Add elapsed time
-			elapsed := m.progress.GetElapsedTime()
-			statusView += fmt.Sprintf("\n⏱️  %ds elapsed", int(elapsed.Seconds()))
+		// Show progress stages as they stream in
+		for _, tracker := range m.progress {
+			statusView += "\n\n" + tracker.View()
 		}
 
 		statusView += "\n\n" + SubtleStyle.Render("Press ESC to cancel")
@@ -348,8 +437,17 @@ func (m MainModel) View() string {
 			statusView,
 		)
 	case stateCompareLoading:
-		loadMsg := fmt.Sprintf("📊 Comparing %s vs %s", m.compareInput1, m.compareInput2)
+		loadMsg := fmt.Sprintf("📊 Comparing %s (%s)", strings.Join(m.compareList, " vs "), m.window.Label())
 		statusView := fmt.Sprintf("%s %s...", m.spinner.View(), loadMsg)
+
+		// One progress column per repository
+		var columns []string
+		for _, tracker := range m.progress {
+			columns = append(columns, BoxStyle.Render(TitleStyle.Render(tracker.Target)+"\n\n"+tracker.View()))
+		}
+		if len(columns) > 0 {
+			statusView += "\n\n" + lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+		}
 		statusView += "\n\n" + SubtleStyle.Render("Press ESC to cancel")
 
 		return lipgloss.Place(
@@ -374,7 +472,7 @@ func (m MainModel) inputView() string {
 			SubtleStyle.Render("Format: owner/repo  •  Press Enter to run")
 
 	if m.err != nil {
-		inputContent += "\n\n" + ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err))
+		inputContent += "\n\n" + renderError(m.err)
 	}
 
 	box := BoxStyle.Render(inputContent)
@@ -392,235 +490,31 @@ func (m MainModel) inputView() string {
 	)
 }
 
-func (m MainModel) analyzeRepo(repoName string) tea.Cmd {
+// analyzeRepo fetches and scores repoName; cancelling ctx aborts any
+// requests still in flight.
+func (m MainModel) analyzeRepo(ctx context.Context, repoName string, events chan<- analysis.Event) tea.Cmd {
 	return func() tea.Msg {
-		parts := strings.Split(repoName, "/")
-		if len(parts) != 2 {
-			return fmt.Errorf("repository must be in owner/repo format")
-		}
+		defer close(events)
 
-		tracker := NewProgressTracker()
-
-		// Stage 1: Fetch repository
-		client := github.NewClient()
-		repo, err := client.GetRepo(parts[0], parts[1])
+		target, err := analysis.ParseTarget(repoName)
 		if err != nil {
 			return err
 		}
-		tracker.NextStage()
-
-		// Stage 2: Analyze commits
-		commits, _ := client.GetCommits(parts[0], parts[1], 365)
-		tracker.NextStage()
-
-		// Stage 3: Analyze contributors
-		contributors, _ := client.GetContributors(parts[0], parts[1])
-		tracker.NextStage()
-
-		// Stage 4: Analyze languages
-		languages, _ := client.GetLanguages(parts[0], parts[1])
-		fileTree, _ := client.GetFileTree(parts[0], parts[1], repo.DefaultBranch)
-		tracker.NextStage()
-
-		// Stage 5: Compute metrics
-		score := analyzer.CalculateHealth(repo, commits)
-		busFactor, busRisk := analyzer.BusFactor(contributors)
-		maturityScore, maturityLevel := analyzer.RepoMaturityScore(repo, len(commits), len(contributors), false)
-		tracker.NextStage()
-
-		// Mark complete
-		tracker.NextStage()
-
-		return AnalysisResult{
-			Repo:          repo,
-			Commits:       commits,
-			Contributors:  contributors,
-			FileTree:      fileTree,
-			Languages:     languages,
-			HealthScore:   score,
-			BusFactor:     busFactor,
-			BusRisk:       busRisk,
-			MaturityScore: maturityScore,
-			MaturityLevel: maturityLevel,
-		}
-	}
-}
-
-func (m MainModel) compareInputView() string {
-	var currentInput string
-	var prompt string
-
-	if m.compareStep == 0 {
-		prompt = "📥 ENTER FIRST REPOSITORY"
-		currentInput = m.compareInput1
-	} else {
-		prompt = "📥 ENTER SECOND REPOSITORY"
-		currentInput = m.compareInput2
-	}
-
-	inputContent := TitleStyle.Render(prompt) + "\n\n"
-
-	if m.compareStep == 1 {
-		inputContent += SubtleStyle.Render("First: "+m.compareInput1) + "\n\n"
-	}
-
-	inputContent += InputStyle.Render("> "+currentInput) + "\n\n"
-	inputContent += SubtleStyle.Render("Format: owner/repo  •  Press Enter to continue  •  ESC to go back")
-
-	if m.err != nil {
-		inputContent += "\n\n" + ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err))
-	}
-
-	box := BoxStyle.Render(inputContent)
-
-	if m.windowWidth == 0 {
-		return box
-	}
-
-	return lipgloss.Place(
-		m.windowWidth,
-		m.windowHeight,
-		lipgloss.Center,
-		lipgloss.Center,
-		box,
-	)
-}
-
-func (m MainModel) compareResultView() string {
-	if m.compareResult == nil || m.compareResult.Repo1.Repo == nil || m.compareResult.Repo2.Repo == nil {
-		return "No comparison data"
-	}
-
-	r1 := m.compareResult.Repo1
-	r2 := m.compareResult.Repo2
-
-	header := TitleStyle.Render(fmt.Sprintf("📊 Comparison: %s vs %s", r1.Repo.FullName, r2.Repo.FullName))
-
-	// Build comparison table
-	rows := []string{
-		fmt.Sprintf("%-20s │ %-25s │ %-25s", "Metric", r1.Repo.FullName, r2.Repo.FullName),
-		strings.Repeat("─", 75),
-		fmt.Sprintf("%-20s │ %-25d │ %-25d", "⭐ Stars", r1.Repo.Stars, r2.Repo.Stars),
-		fmt.Sprintf("%-20s │ %-25d │ %-25d", "🍴 Forks", r1.Repo.Forks, r2.Repo.Forks),
-		fmt.Sprintf("%-20s │ %-25d │ %-25d", "📦 Commits (1y)", len(r1.Commits), len(r2.Commits)),
-		fmt.Sprintf("%-20s │ %-25d │ %-25d", "👥 Contributors", len(r1.Contributors), len(r2.Contributors)),
-		fmt.Sprintf("%-20s │ %-25s │ %-25s", "💚 Health Score", fmt.Sprintf("%d", r1.HealthScore), fmt.Sprintf("%d", r2.HealthScore)),
-		fmt.Sprintf("%-20s │ %-25s │ %-25s", "⚠️ Bus Factor", fmt.Sprintf("%d (%s)", r1.BusFactor, r1.BusRisk), fmt.Sprintf("%d (%s)", r2.BusFactor, r2.BusRisk)),
-		fmt.Sprintf("%-20s │ %-25s │ %-25s", "🏗️ Maturity", fmt.Sprintf("%s (%d)", r1.MaturityLevel, r1.MaturityScore), fmt.Sprintf("%s (%d)", r2.MaturityLevel, r2.MaturityScore)),
-	}
-
-	tableContent := strings.Join(rows, "\n")
-	tableBox := BoxStyle.Render(tableContent)
-
-	// Verdict
-	var verdict string
-	if r1.MaturityScore > r2.MaturityScore {
-		verdict = fmt.Sprintf("➡️ %s appears more mature and stable.", r1.Repo.FullName)
-	} else if r2.MaturityScore > r1.MaturityScore {
-		verdict = fmt.Sprintf("➡️ %s appears more mature and stable.", r2.Repo.FullName)
-	} else {
-		verdict = "➡️ Both repositories are similarly mature."
-	}
-	verdictBox := BoxStyle.Render("📌 Verdict\n" + verdict)
-
-	footer := SubtleStyle.Render("q/ESC: back to menu")
-
-	content := lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		tableBox,
-		verdictBox,
-		footer,
-	)
-
-	if m.windowWidth == 0 {
-		return content
-	}
-
-	return lipgloss.Place(
-		m.windowWidth,
-		m.windowHeight,
-		lipgloss.Center,
-		lipgloss.Center,
-		content,
-	)
-}
-
-func (m MainModel) compareRepos(repo1Name, repo2Name string) tea.Cmd {
-	return func() tea.Msg {
-		parts1 := strings.Split(repo1Name, "/")
-		parts2 := strings.Split(repo2Name, "/")
-
-		if len(parts1) != 2 {
-			return fmt.Errorf("first repository must be in owner/repo format")
-		}
-		if len(parts2) != 2 {
-			return fmt.Errorf("second repository must be in owner/repo format")
-		}
-
-		client := github.NewClient()
-
-		// Analyze first repo
-		repo1, err := client.GetRepo(parts1[0], parts1[1])
-		if err != nil {
-			return fmt.Errorf("failed to fetch %s: %w", repo1Name, err)
-		}
-		commits1, _ := client.GetCommits(parts1[0], parts1[1], 365)
-		contributors1, _ := client.GetContributors(parts1[0], parts1[1])
-		languages1, _ := client.GetLanguages(parts1[0], parts1[1])
-		fileTree1, _ := client.GetFileTree(parts1[0], parts1[1], repo1.DefaultBranch)
-		score1 := analyzer.CalculateHealth(repo1, commits1)
-		busFactor1, busRisk1 := analyzer.BusFactor(contributors1)
-		maturityScore1, maturityLevel1 := analyzer.RepoMaturityScore(repo1, len(commits1), len(contributors1), false)
-
-		result1 := AnalysisResult{
-			Repo:          repo1,
-			Commits:       commits1,
-			Contributors:  contributors1,
-			FileTree:      fileTree1,
-			Languages:     languages1,
-			HealthScore:   score1,
-			BusFactor:     busFactor1,
-			BusRisk:       busRisk1,
-			MaturityScore: maturityScore1,
-			MaturityLevel: maturityLevel1,
-		}
 
-		// Analyze second repo
-		repo2, err := client.GetRepo(parts2[0], parts2[1])
+		result, err := analysis.Analyze(ctx, m.client, target, analysis.Options{
+			HealthProfile: m.healthProfile,
+			Progress:      sendProgress(ctx, events),
+		})
 		if err != nil {
-			return fmt.Errorf("failed to fetch %s: %w", repo2Name, err)
-		}
-		commits2, _ := client.GetCommits(parts2[0], parts2[1], 365)
-		contributors2, _ := client.GetContributors(parts2[0], parts2[1])
-		languages2, _ := client.GetLanguages(parts2[0], parts2[1])
-		fileTree2, _ := client.GetFileTree(parts2[0], parts2[1], repo2.DefaultBranch)
-		score2 := analyzer.CalculateHealth(repo2, commits2)
-		busFactor2, busRisk2 := analyzer.BusFactor(contributors2)
-		maturityScore2, maturityLevel2 := analyzer.RepoMaturityScore(repo2, len(commits2), len(contributors2), false)
-
-		result2 := AnalysisResult{
-			Repo:          repo2,
-			Commits:       commits2,
-			Contributors:  contributors2,
-			FileTree:      fileTree2,
-			Languages:     languages2,
-			HealthScore:   score2,
-			BusFactor:     busFactor2,
-			BusRisk:       busRisk2,
-			MaturityScore: maturityScore2,
-			MaturityLevel: maturityLevel2,
-		}
-
-		return CompareResult{
-			Repo1: result1,
-			Repo2: result2,
+			return err
 		}
+		return newAnalysisResult(result, m.client)
 	}
 }
 
-func Run() error {
-	p := tea.NewProgram(NewMainModel(), tea.WithAltScreen())
+// Run starts the interactive TUI using client for all API calls.
+func Run(client *github.Client, opts Options) error {
+	p := tea.NewProgram(NewMainModel(client, opts), tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Compare table column widths; repo columns shrink towards the minimum to
+// fit the window
+const (
+	compareNameWidth   = 16
+	compareMaxColWidth = 24
+	compareMinColWidth = 10
+	scoreBarWidth      = 5
+)
+
+// addCompareRepo validates name and appends it to the comparison list
+func (m *MainModel) addCompareRepo(name string) error {
+	target, err := analysis.ParseTarget(name)
+	if err != nil {
+		return err
+	}
+	for _, existing := range m.compareList {
+		if strings.EqualFold(existing, target.String()) {
+			return fmt.Errorf("%s is already in the comparison", target)
+		}
+	}
+	m.compareList = append(m.compareList, target.String())
+	return nil
+}
+
+func (m MainModel) compareInputView() string {
+	inputContent := TitleStyle.Render("📥 ENTER REPOSITORIES TO COMPARE") + "\n\n"
+
+	for i, name := range m.compareList {
+		inputContent += SubtleStyle.Render(fmt.Sprintf("%d. %s", i+1, name)) + "\n"
+	}
+	if len(m.compareList) > 0 {
+		inputContent += "\n"
+	}
+
+	inputContent += InputStyle.Render("> "+m.compareInput) + "\n\n"
+	inputContent += "Commit window: " + SelectedStyle.Render(m.window.Label()) + SubtleStyle.Render("  (Tab to change)") + "\n\n"
+	hint := "Format: owner/repo  •  Enter to add"
+	if len(m.compareList) >= 2 {
+		hint += "  •  Enter on an empty line to compare"
+	}
+	if len(m.compareList) > 0 {
+		hint += "  •  ESC to remove the last"
+	} else {
+		hint += "  •  ESC to go back"
+	}
+	inputContent += SubtleStyle.Render(hint)
+
+	if m.err != nil {
+		inputContent += "\n\n" + renderError(m.err)
+	}
+
+	box := BoxStyle.Render(inputContent)
+
+	if m.windowWidth == 0 {
+		return box
+	}
+
+	return lipgloss.Place(
+		m.windowWidth,
+		m.windowHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		box,
+	)
+}
+
+func (m MainModel) compareResultView() string {
+	if m.compareResult == nil || len(m.compareResult.Repos) == 0 {
+		return "No comparison data"
+	}
+	c := m.compareResult.Comparison
+
+	header := TitleStyle.Render(fmt.Sprintf("📊 Comparison: %s (%s)", strings.Join(c.Repos, " vs "), c.Window.Label()))
+	colWidth := m.compareColWidth(c)
+
+	// Metric matrix, best value in each row highlighted
+	rows := []string{compareRow("Metric · "+c.Window.Name, c.Repos, colWidth, func(int) lipgloss.Style { return TitleStyle })}
+	rows = append(rows, strings.Repeat("─", compareNameWidth+len(c.Repos)*(colWidth+3)))
+	for _, metric := range c.Metrics {
+		rows = append(rows, compareRow(metric.Name, metric.Labels, colWidth, func(i int) lipgloss.Style {
+			if metric.IsLeader(i) {
+				return SelectedStyle
+			}
+			return NormalStyle
+		}))
+	}
+	for i, a := range c.Activity {
+		if a.Truncated {
+			rows = append(rows, SubtleStyle.Render(fmt.Sprintf("+ %s: only the latest %d commits, back to %s", c.Repos[i], a.Commits, a.Start.Format("2006-01-02"))))
+		}
+	}
+	tableBox := BoxStyle.Render(strings.Join(rows, "\n"))
+
+	// Normalized scores per dimension, as bars
+	scoreRows := []string{TitleStyle.Render("🎯 Scores (0-100) · " + c.Window.Name)}
+	for _, d := range analysis.Dimensions {
+		cells := make([]string, len(c.Scores))
+		best := -1.0
+		for i, s := range c.Scores {
+			if score, ok := s.Scores[d]; ok {
+				cells[i] = scoreBar(score)
+				best = max(best, score)
+			} else {
+				cells[i] = "-"
+			}
+		}
+		name := fmt.Sprintf("%s ×%g", d.Label(), c.Weights[d])
+		scoreRows = append(scoreRows, compareRow(name, cells, colWidth, func(i int) lipgloss.Style {
+			if score, ok := c.Scores[i].Scores[d]; ok && score == best && len(c.Scores) > 1 {
+				return SelectedStyle
+			}
+			return NormalStyle
+		}))
+	}
+	overall := make([]string, len(c.Scores))
+	for i, s := range c.Scores {
+		overall[i] = scoreBar(s.Overall)
+	}
+	scoreRows = append(scoreRows, compareRow("Overall", overall, colWidth, func(i int) lipgloss.Style {
+		if c.Scores[i].Rank == 1 {
+			return SelectedStyle
+		}
+		return InputStyle
+	}))
+	scoreBox := BoxStyle.Render(strings.Join(scoreRows, "\n"))
+
+	// Ranking and verdict
+	ranking := []string{TitleStyle.Render("🏆 Ranking")}
+	for _, i := range c.Ranking {
+		s := c.Scores[i]
+		line := fmt.Sprintf("%d. %s (%.0f)", s.Rank, s.Repo, s.Overall)
+		if s.Rank == 1 {
+			line = SelectedStyle.Render(line)
+		}
+		ranking = append(ranking, line)
+	}
+	verdict := "➡️ The top repositories are tied overall."
+	if leader := c.Leader(); leader != "" {
+		verdict = fmt.Sprintf("➡️ %s comes out ahead overall.", leader)
+	}
+	ranking = append(ranking, "", verdict)
+	rankingBox := BoxStyle.Render(strings.Join(ranking, "\n"))
+
+	footer := SubtleStyle.Render("Weights: " + c.Weights.String() + "  •  q/ESC: back to menu")
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		tableBox,
+		lipgloss.JoinHorizontal(lipgloss.Top, scoreBox, rankingBox),
+		footer,
+	)
+
+	if m.windowWidth == 0 {
+		return content
+	}
+
+	return lipgloss.Place(
+		m.windowWidth,
+		m.windowHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		content,
+	)
+}
+
+// compareColWidth sizes the repo columns to their widest cell, shrinking
+// them when there are too many repos for the window
+func (m MainModel) compareColWidth(c analysis.Comparison) int {
+	width := compareMinColWidth
+	for _, name := range c.Repos {
+		width = max(width, lipgloss.Width(name))
+	}
+	for _, metric := range c.Metrics {
+		for _, label := range metric.Labels {
+			width = max(width, lipgloss.Width(label))
+		}
+	}
+	width = min(width, compareMaxColWidth)
+
+	if m.windowWidth > 0 && len(c.Repos) > 0 {
+		// Box border and padding take 10 columns, each separator 3
+		fit := (m.windowWidth - 10 - compareNameWidth) / len(c.Repos)
+		width = max(min(width, fit-3), compareMinColWidth)
+	}
+	return width
+}
+
+// compareRow lays out one table row, styling each repo's cell
+func compareRow(name string, cells []string, width int, style func(i int) lipgloss.Style) string {
+	row := lipgloss.NewStyle().Width(compareNameWidth).Render(ansi.Truncate(name, compareNameWidth, "…"))
+	for i, cell := range cells {
+		row += " │ " + style(i).Width(width).Render(ansi.Truncate(cell, width, "…"))
+	}
+	return row
+}
+
+// scoreBar draws a 0-100 score as a short bar followed by the number
+func scoreBar(score float64) string {
+	filled := int(score/100*scoreBarWidth + 0.5)
+	filled = min(max(filled, 0), scoreBarWidth)
+	return fmt.Sprintf("%s%s %.0f", strings.Repeat("█", filled), strings.Repeat("░", scoreBarWidth-filled), score)
+}
+
+// compareRepos analyzes every repository together and ranks them
+func (m MainModel) compareRepos(ctx context.Context, repoNames []string, events chan<- analysis.Event) tea.Cmd {
+	return func() tea.Msg {
+		defer close(events)
+
+		var targets []analysis.Target
+		for _, name := range repoNames {
+			target, err := analysis.ParseTarget(name)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, target)
+		}
+
+		// All repositories are fetched together under one concurrency cap,
+		// the same way the compare command fetches them
+		opts := analysis.CompareOptions(m.window, m.healthProfile)
+		opts.Progress = sendProgress(ctx, events)
+		results := analysis.Run(ctx, m.client, targets, opts)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, r := range results {
+			if err := r.Err(); err != nil {
+				return fmt.Errorf("%s: %w", r.Target, err)
+			}
+		}
+
+		compared := CompareResult{Comparison: analysis.Compare(results, m.window, m.weights)}
+		for _, r := range results {
+			compared.Repos = append(compared.Repos, newAnalysisResult(r, m.client))
+		}
+		return compared
+	}
+}
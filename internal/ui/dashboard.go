@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
 	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -21,9 +22,16 @@ const (
 	viewActivity
 	viewContributors
 	viewRecruiter
-	viewAPIStatus
+	viewReleases
+	viewIssues
+	viewPulls
+	viewSecurity
+	viewAPIStatus // Keep last: tab navigation stops here
 )
 
+// dashboardTabs names each view in tab order
+var dashboardTabs = []string{"Overview", "Repo", "Languages", "Activity", "Contributors", "Recruiter", "Releases", "Issues", "PRs", "Security", "API"}
+
 type DashboardModel struct {
 	data        AnalysisResult
 	BackToMenu  bool
@@ -95,11 +103,26 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "j":
 			if m.showExport {
+				data := m.data
 				return m, func() tea.Msg {
+					if err := ExportJSON(data, "analysis.json"); err != nil {
+						return exportMsg{err, ""}
+					}
 					return exportMsg{nil, "Exported to analysis.json"}
 				}
 			}
 
+		case "m":
+			if m.showExport {
+				data := m.data
+				return m, func() tea.Msg {
+					if err := ExportMarkdown(data, "analysis.md"); err != nil {
+						return exportMsg{err, ""}
+					}
+					return exportMsg{nil, "Exported to analysis.md"}
+				}
+			}
+
 		case "f":
 			return m, func() tea.Msg { return "switch_to_tree" }
 
@@ -110,34 +133,15 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		// View switching keybindings
-		case "1":
-			m.currentView = viewOverview
-			m.showHelp = false
-			m.showExport = false
-		case "2":
-			m.currentView = viewRepo
-			m.showHelp = false
-			m.showExport = false
-		case "3":
-			m.currentView = viewLanguages
-			m.showHelp = false
-			m.showExport = false
-		case "4":
-			m.currentView = viewActivity
-			m.showHelp = false
-			m.showExport = false
-		case "5":
-			m.currentView = viewContributors
-			m.showHelp = false
-			m.showExport = false
-		case "6":
-			m.currentView = viewRecruiter
-			m.showHelp = false
-			m.showExport = false
-		case "7":
-			m.currentView = viewAPIStatus
-			m.showHelp = false
-			m.showExport = false
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0", "a":
+			for i := range dashboardTabs {
+				if tabKey(i) == msg.String() {
+					m.currentView = dashboardView(i)
+					m.showHelp = false
+					m.showExport = false
+					break
+				}
+			}
 
 		// Arrow key navigation between views
 		case "right", "l":
@@ -183,6 +187,14 @@ func (m DashboardModel) View() string {
 		content = m.contributorsView()
 	case viewRecruiter:
 		content = m.recruiterView()
+	case viewReleases:
+		content = m.releasesView()
+	case viewIssues:
+		content = m.issuesView()
+	case viewPulls:
+		content = m.pullsView()
+	case viewSecurity:
+		content = m.securityView()
 	case viewAPIStatus:
 		content = m.apiStatusView()
 	}
@@ -192,7 +204,7 @@ func (m DashboardModel) View() string {
 		content = lipgloss.JoinVertical(
 			lipgloss.Left,
 			content,
-			BoxStyle.Render("📥 Export:\n[J] JSON"),
+			BoxStyle.Render("📥 Export:\n[J] JSON\n[M] Markdown"),
 		)
 	}
 
@@ -202,7 +214,7 @@ func (m DashboardModel) View() string {
 
 	// Navigation tabs
 	tabs := m.renderTabs()
-	footer := SubtleStyle.Render("←→/hl: switch view • 1-6: jump to view • e: export • f: file tree • ?: help • q: back")
+	footer := SubtleStyle.Render("←→/hl: switch view • 0-9/a: jump to view • e: export • f: file tree • ?: help • q: back")
 
 	fullContent := lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -224,12 +236,26 @@ func (m DashboardModel) View() string {
 	)
 }
 
+// tabKeys are the keys that jump to each tab, in tab order: the number row
+// for the first ten, then a for API status
+var tabKeys = []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "0", "a"}
+
+// tabKey is the key that jumps to the i-th tab
+func tabKey(i int) string {
+	if i < len(tabKeys) {
+		return tabKeys[i]
+	}
+	return ""
+}
+
 func (m DashboardModel) renderTabs() string {
-	views := []string{"Overview", "Repo", "Languages", "Activity", "Contributors", "Recruiter", "API"}
 	var tabs []string
 
-	for i, name := range views {
-		tab := fmt.Sprintf(" %d:%s ", i+1, name)
+	for i, name := range dashboardTabs {
+		tab := fmt.Sprintf(" %s ", name)
+		if key := tabKey(i); key != "" {
+			tab = fmt.Sprintf(" %s:%s ", key, name)
+		}
 		if dashboardView(i) == m.currentView {
 			tabs = append(tabs, SelectedStyle.Render(tab))
 		} else {
@@ -246,12 +272,13 @@ func (m DashboardModel) overviewView() string {
 	)
 
 	metrics := fmt.Sprintf(
-		"Health Score: %d\nBus Factor: %d (%s)\nMaturity: %s (%d)",
+		"Health Score: %d\nBus Factor: %d (%s)\nMaturity: %s (%d)\n%s",
 		m.data.HealthScore,
 		m.data.BusFactor,
 		m.data.BusRisk,
 		m.data.MaturityLevel,
 		m.data.MaturityScore,
+		SubtleStyle.Render(m.data.Maturity.ConfidenceLabel()+" confidence"),
 	)
 	metricsBox := BoxStyle.Render(metrics)
 
@@ -263,9 +290,39 @@ func (m DashboardModel) overviewView() string {
 		lipgloss.Left,
 		header,
 		lipgloss.JoinHorizontal(lipgloss.Top, metricsBox, chartBox),
+		m.healthBreakdownView(),
 	)
 }
 
+// healthBreakdownView explains the health score rule by rule
+func (m DashboardModel) healthBreakdownView() string {
+	report := m.data.Health
+	if len(report.Checks) == 0 {
+		return ""
+	}
+
+	lines := []string{TitleStyle.Render(fmt.Sprintf("🩺 Health breakdown · %s profile", report.Profile))}
+	for _, c := range report.Checks {
+		mark := "➖"
+		switch {
+		case c.Skipped:
+			mark = "❔"
+		case c.Points >= c.MaxPoints:
+			mark = "✅"
+		case c.Points == 0:
+			mark = "❌"
+		}
+		line := fmt.Sprintf("%s %-22s %4g/%-3g", mark, c.Name, c.Points, c.MaxPoints)
+		if c.Skipped {
+			line = fmt.Sprintf("%s %-22s %s", mark, c.Name, SubtleStyle.Render("not checked"))
+		} else if c.Detail != "" {
+			line += "  " + SubtleStyle.Render(c.Detail)
+		}
+		lines = append(lines, line)
+	}
+	return BoxStyle.Render(strings.Join(lines, "\n"))
+}
+
 func (m DashboardModel) repoView() string {
 	header := TitleStyle.Render("📦 Repository Details")
 
@@ -290,7 +347,29 @@ func (m DashboardModel) repoView() string {
 		m.data.Repo.HTMLURL,
 	)
 
-	return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render(info))
+	return lipgloss.JoinVertical(lipgloss.Left, header,
+		lipgloss.JoinHorizontal(lipgloss.Top, BoxStyle.Render(info), m.maturityView()))
+}
+
+// maturityView lists the normalized signals behind the maturity level
+func (m DashboardModel) maturityView() string {
+	maturity := m.data.Maturity
+	if len(maturity.Signals) == 0 {
+		return ""
+	}
+
+	lines := []string{
+		TitleStyle.Render(fmt.Sprintf("🏗️ Maturity: %s (%d)", maturity.Level, maturity.Score)),
+		SubtleStyle.Render(fmt.Sprintf("%s confidence, %.0f%% of signals", maturity.ConfidenceLabel(), maturity.Confidence*100)),
+	}
+	for _, s := range maturity.Signals {
+		if !s.Known {
+			lines = append(lines, fmt.Sprintf("❔ %-22s %s", s.Name, SubtleStyle.Render("not checked")))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %3d  %s", lipgloss.NewStyle().Width(25).Render(s.Name), s.Score, SubtleStyle.Render(s.Detail)))
+	}
+	return BoxStyle.Render(strings.Join(lines, "\n"))
 }
 
 func (m DashboardModel) languagesView() string {
@@ -373,10 +452,51 @@ func (m DashboardModel) contributorsView() string {
 
 	summary := fmt.Sprintf("\nTotal Contributors: %d", len(m.data.Contributors))
 	lines = append(lines, summary)
+	lines = append(lines, truckFactorLines(m.data.TruckFactor)...)
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render(strings.Join(lines, "\n")))
 }
 
+// truckFactorLines lists the key people and, when authorship was computed,
+// who owns each top-level directory
+func truckFactorLines(tf analyzer.TruckFactor) []string {
+	if tf.Mode == "" {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("\nTruck Factor: %d (%s)", tf.Value, tf.Risk)}
+	if tf.Mode == analyzer.TruckModeHeuristic {
+		lines = append(lines, SubtleStyle.Render("Estimated from the top contributor's share of commits"))
+	} else {
+		lines = append(lines, SubtleStyle.Render(fmt.Sprintf(
+			"From authorship of %d files in %d sampled commits; loss of these people orphans over %.0f%% of files",
+			tf.FilesAnalyzed, tf.CommitsSampled, tf.Threshold*100)))
+	}
+
+	for _, p := range tf.KeyPeople {
+		if tf.Mode == analyzer.TruckModeHeuristic {
+			lines = append(lines, fmt.Sprintf("  🔑 %-20s %.0f%% of commits", p.Login, p.Share*100))
+		} else {
+			lines = append(lines, fmt.Sprintf("  🔑 %-20s authors %d files (%.0f%%)", p.Login, p.Files, p.Share*100))
+		}
+	}
+
+	if len(tf.Directories) > 0 {
+		lines = append(lines, "\nDirectory Ownership:")
+		for i, d := range tf.Directories {
+			if i == 10 {
+				break
+			}
+			line := fmt.Sprintf("  %-20s %4d files  %-18s %.0f%%", d.Dir, d.Files, d.Owner, d.Share*100)
+			if d.Orphaned {
+				line += ErrorStyle.Render("  only key people")
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
 func (m DashboardModel) recruiterView() string {
 	header := TitleStyle.Render("👔 Recruiter Summary")
 
@@ -399,6 +519,8 @@ func (m DashboardModel) recruiterView() string {
 			"🏗️ Maturity: %s (%d)\n"+
 			"⚠️ Bus Factor: %d - %s\n"+
 			"🔥 Activity: %s\n"+
+			"🐛 Issue Health: %s\n"+
+			"🔀 PR Health: %s\n"+
 			"💚 Health Score: %d/100",
 		m.data.Repo.FullName,
 		m.data.Repo.Stars,
@@ -408,6 +530,8 @@ func (m DashboardModel) recruiterView() string {
 		m.data.MaturityLevel, m.data.MaturityScore,
 		m.data.BusFactor, m.data.BusRisk,
 		activityLevel,
+		m.data.IssueHealth.Summary(),
+		m.data.PRHealth.Summary(),
 		m.data.HealthScore,
 	)
 
@@ -420,7 +544,7 @@ func (m DashboardModel) helpView() string {
 	help := `
 Dashboard Navigation:
   ←/→ or h/l    Switch between views
-  1-7           Jump to specific view
+  0-9, a        Jump to specific view
   
 Views:
   1  Overview     - Health, Bus Factor, Maturity
@@ -429,11 +553,16 @@ Views:
   4  Activity     - Commit activity chart
   5  Contributors - Top contributors
   6  Recruiter    - Summary for recruiters
-  7  API Status   - GitHub API rate limits
+  7  Releases     - Release cadence and versioning
+  8  Issues       - Issue tracker health
+  9  PRs          - Pull request review and merge health
+  0  Security     - Security posture checklist
+  a  API Status   - GitHub API rate limits
 
 Actions:
   e             Toggle export menu
   j             Export to JSON (when export menu open)
+  m             Export to Markdown (when export menu open)
   f             Open file tree
   r             Refresh data
   ?/h           Toggle this help
@@ -455,17 +584,22 @@ func (m DashboardModel) apiStatusView() string {
 
 	// Check if authenticated
 	mode := "Unauthenticated (60 req/hour)"
-	if m.data.Repo != nil && m.data.Repo.Private {
-		mode = "Authenticated (5000 req/hour)"
-	} else {
-		// Simple check - if we got detailed data, likely authenticated
-		if len(m.data.Contributors) > 30 {
-			mode = "Authenticated (5000 req/hour)"
+	budget := "Requests: unknown"
+	if rl := m.data.RateLimit; rl != nil {
+		core := rl.Resources.Core
+		if core.Limit > 60 {
+			mode = fmt.Sprintf("Authenticated (%d req/hour)", core.Limit)
 		}
+		budget = fmt.Sprintf(
+			"Requests: %d / %d remaining\nResets At: %s",
+			core.Remaining, core.Limit, rl.ResetTime().Format("15:04"),
+		)
+	} else if m.data.Repo != nil && m.data.Repo.Private {
+		mode = "Authenticated (5000 req/hour)"
 	}
 
 	info := fmt.Sprintf(
-		"Mode: %s\n\n"+
+		"Mode: %s\n%s\n\n"+
 			"Data Fetched:\n"+
 			"  • Repository info: ✓\n"+
 			"  • Commits (1 year): %d\n"+
@@ -475,11 +609,246 @@ func (m DashboardModel) apiStatusView() string {
 			"Tip: Set GITHUB_TOKEN env variable\n"+
 			"for higher rate limits (5000/hour)",
 		mode,
+		budget,
 		len(m.data.Commits),
 		len(m.data.Contributors),
 		len(m.data.Languages),
 		len(m.data.FileTree),
 	)
 
+	// List fetches that failed so gaps in other views are explained
+	if len(m.data.FetchErrors) > 0 {
+		info += "\n\n" + ErrorStyle.Render("Failed fetches:")
+		for _, f := range analysis.AllFetches {
+			if err, ok := m.data.FetchErrors[f]; ok {
+				info += fmt.Sprintf("\n  • %s: %v", f, err)
+			}
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render(info))
+}
+
+func (m DashboardModel) releasesView() string {
+	header := TitleStyle.Render("🏷️ Releases")
+	stats := m.data.ReleaseStats
+
+	if !stats.HasReleases() {
+		return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render("No releases or tags published"))
+	}
+
+	lastRelease := "none"
+	if stats.Releases > 0 {
+		lastRelease = fmt.Sprintf("%s (%s, %d days ago)",
+			stats.LastRelease, stats.LastReleaseAt.Format("2006-01-02"), stats.DaysSinceLastRelease)
+	}
+
+	info := fmt.Sprintf(
+		"Releases: %d (%d pre-release, %.0f%%)\n"+
+			"Tags: %d\n"+
+			"Latest: %s\n"+
+			"Cadence: %s (every %.0f days on average)\n"+
+			"Semver tags: %d (%.0f%%)\n"+
+			"Assets: %d",
+		stats.Releases, stats.PreReleases, stats.PreReleaseRatio*100,
+		stats.Tags,
+		lastRelease,
+		stats.Cadence, stats.AvgDaysBetween,
+		stats.SemverTags, stats.SemverCompliance*100,
+		stats.TotalAssets,
+	)
+
+	// Most recent releases, newest first as returned by the API
+	var recent []string
+	for _, r := range m.data.Releases {
+		if r.Draft {
+			continue
+		}
+		line := fmt.Sprintf("%-20s %s", r.TagName, r.PublishedAt.Format("2006-01-02"))
+		if r.Prerelease {
+			line += " (pre)"
+		}
+		recent = append(recent, line)
+		if len(recent) == 10 {
+			break
+		}
+	}
+	if len(recent) > 0 {
+		info += "\n\nRecent:\n" + strings.Join(recent, "\n")
+	}
+
 	return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render(info))
 }
+
+func (m DashboardModel) issuesView() string {
+	header := TitleStyle.Render("🐛 Issue Health")
+	h := m.data.IssueHealth
+
+	if h.Total == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render("No issues found"))
+	}
+
+	firstResponse := "no responses"
+	switch {
+	case h.ResponseKnown == 0:
+		firstResponse = "no comment data"
+	case h.Responded > 0:
+		firstResponse = fmt.Sprintf("%s (%d of %d issues answered)", formatDays(h.MedianFirstResponse), h.Responded, h.ResponseKnown)
+	}
+	if h.ResponsePartial {
+		firstResponse += SubtleStyle.Render(fmt.Sprintf(", %d older issues without comment data", h.Total-h.ResponseKnown))
+	}
+	timeToClose := "n/a"
+	if h.Closed > 0 {
+		timeToClose = formatDays(h.MedianTimeToClose)
+	}
+
+	info := fmt.Sprintf(
+		"Grade: %s - %s (%d/100)\n\n"+
+			"Issues analyzed: %d\n"+
+			"Open: %d  Closed: %d  (%.2f open per closed)\n"+
+			"Median time to close: %s\n"+
+			"Median first response: %s\n"+
+			"Stale (no update in %d days): %d",
+		h.Grade, h.Verdict, h.Score,
+		h.Total,
+		h.Open, h.Closed, h.OpenClosedRatio,
+		timeToClose,
+		firstResponse,
+		int(analyzer.StaleAfter.Hours()/24), h.Stale,
+	)
+
+	if len(h.Labels) > 0 {
+		var labels []string
+		for i, l := range h.Labels {
+			if i == 10 {
+				break
+			}
+			labels = append(labels, fmt.Sprintf("%-24s %d", l.Name, l.Count))
+		}
+		info += "\n\nTop labels:\n" + strings.Join(labels, "\n")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render(info))
+}
+
+func (m DashboardModel) pullsView() string {
+	header := TitleStyle.Render("🔀 Pull Requests")
+	h := m.data.PRHealth
+
+	if h.Total == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render("No pull requests found"))
+	}
+
+	timeToMerge := "n/a"
+	if h.Merged > 0 {
+		timeToMerge = formatDays(h.MedianTimeToMerge)
+	}
+	firstReview := "no reviews"
+	switch {
+	case h.Sampled == 0:
+		firstReview = "n/a (no review sample)"
+	case h.Reviewed > 0:
+		firstReview = fmt.Sprintf("%s (%d reviewed)", formatDays(h.MedianFirstReview), h.Reviewed)
+	}
+	approved := "n/a (no merged pull requests sampled)"
+	if h.SampledMerged > 0 {
+		approved = fmt.Sprintf("%.0f%% (of %d sampled)", h.ReviewCoverage*100, h.SampledMerged)
+	}
+	external := "no outside contributions"
+	if h.External > 0 {
+		external = fmt.Sprintf("%.0f%% (%d of %d merged)", h.ExternalAcceptance*100, h.ExternalMerged, h.External)
+	}
+
+	info := fmt.Sprintf(
+		"Grade: %s - %s (%d/100)\n\n"+
+			"Pull requests analyzed: %d\n"+
+			"Open: %d  Merged: %d  Closed unmerged: %d\n"+
+			"Merge rate: %.0f%%\n"+
+			"Median time to merge: %s\n"+
+			"Median first review: %s\n"+
+			"Approved before merge: %s\n"+
+			"External acceptance: %s",
+		h.Grade, h.Verdict, h.Score,
+		h.Total,
+		h.Open, h.Merged, h.ClosedUnmerged,
+		h.MergeRate*100,
+		timeToMerge,
+		firstReview,
+		approved,
+		external,
+	)
+
+	if h.Sampled > 0 {
+		maxCount := 0
+		for _, b := range h.Sizes {
+			if b.Count > maxCount {
+				maxCount = b.Count
+			}
+		}
+		lines := []string{"\n\nSize (lines changed, sampled):"}
+		for _, b := range h.Sizes {
+			limit := fmt.Sprintf("<%d", b.Max)
+			if b.Max == 0 {
+				limit = fmt.Sprintf(">=%d", h.Sizes[len(h.Sizes)-2].Max)
+			}
+			bar := ""
+			if maxCount > 0 {
+				bar = strings.Repeat("█", b.Count*20/maxCount)
+			}
+			lines = append(lines, fmt.Sprintf("%-3s %-6s %s %d", b.Label, limit, bar, b.Count))
+		}
+		info += strings.Join(lines, "\n")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render(info))
+}
+
+func (m DashboardModel) securityView() string {
+	header := TitleStyle.Render("🔒 Security")
+	report := m.data.Security
+
+	lines := []string{fmt.Sprintf("Score: %d/100 - %s (%s)\n", report.Score, report.Grade, report.Verdict)}
+	for _, c := range report.Checks {
+		mark := "❌"
+		switch {
+		case c.Skipped:
+			mark = "❔"
+		case c.Passed:
+			mark = "✅"
+		}
+		line := fmt.Sprintf("%s %-42s %2d/%-2d", mark, c.Name, c.Points, c.MaxPoints)
+		if c.Skipped {
+			line = fmt.Sprintf("%s %-42s %s", mark, c.Name, SubtleStyle.Render("not checked"))
+		}
+		if c.Detail != "" {
+			line += "  " + SubtleStyle.Render(c.Detail)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(report.Findings) > 0 {
+		lines = append(lines, fmt.Sprintf("\nFindings (%d):", len(report.Findings)))
+		for i, f := range report.Findings {
+			if i == 12 {
+				lines = append(lines, SubtleStyle.Render(fmt.Sprintf("  … and %d more", len(report.Findings)-i)))
+				break
+			}
+			severity := strings.ToUpper(f.Severity)
+			if f.Severity == analyzer.SeverityHigh {
+				severity = ErrorStyle.Render(severity)
+			}
+			lines = append(lines, fmt.Sprintf("  [%s] %s: %s", severity, f.File, f.Message))
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, BoxStyle.Render(strings.Join(lines, "\n")))
+}
+
+// formatDays renders a duration in days, or hours when under a day
+func formatDays(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%.0fh", d.Hours())
+	}
+	return fmt.Sprintf("%.1f days", d.Hours()/24)
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEveryTabHasAKey(t *testing.T) {
+	// Keys the dashboard already uses for actions
+	actions := map[string]bool{"q": true, "h": true, "l": true, "e": true, "j": true, "m": true, "f": true, "r": true, "?": true}
+	seen := map[string]bool{}
+
+	for i, name := range dashboardTabs {
+		key := tabKey(i)
+		if key == "" {
+			t.Errorf("%s has no key", name)
+			continue
+		}
+		if seen[key] || actions[key] {
+			t.Errorf("%s key %q is already taken", name, key)
+		}
+		seen[key] = true
+
+		m := NewDashboardModel()
+		m.showHelp = true
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		got := updated.(DashboardModel)
+		if got.currentView != dashboardView(i) || got.showHelp {
+			t.Errorf("key %q showed view %d, want %s", key, got.currentView, name)
+		}
+	}
+	if tabKey(len(dashboardTabs)) != "" {
+		t.Error("a key is left over past the last tab")
+	}
+}
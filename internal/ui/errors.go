@@ -0,0 +1,17 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// renderError formats err for input screens, adding a hint on how to fix
+// it when the error is one the github package recognises.
+func renderError(err error) string {
+	content := ErrorStyle.Render(fmt.Sprintf("Error: %v", err))
+	if hint := github.Guidance(err); hint != "" {
+		content += "\n" + SubtleStyle.Render("💡 "+hint)
+	}
+	return content
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 )
 
 func ExportJSON(data AnalysisResult, filename string) error {
@@ -27,9 +29,27 @@ func ExportMarkdown(data AnalysisResult, filename string) error {
 
 	md := fmt.Sprintf("# Analysis for %s\n\n", data.Repo.FullName)
 	md += fmt.Sprintf("## Health Score: %d\n", data.HealthScore)
+	for _, c := range data.Health.Checks {
+		if c.Skipped {
+			md += fmt.Sprintf("- %s: not checked\n", c.Name)
+		} else {
+			md += fmt.Sprintf("- %s: %g/%g (%s)\n", c.Name, c.Points, c.MaxPoints, c.Detail)
+		}
+	}
 	md += fmt.Sprintf("## Bus Factor: %d (%s)\n", data.BusFactor, data.BusRisk)
 	md += fmt.Sprintf("## Maturity: %s (%d)\n", data.MaturityLevel, data.MaturityScore)
-	
+	if len(data.Maturity.Signals) > 0 {
+		md += fmt.Sprintf("Confidence: %s (%.0f%% of signals)\n", data.Maturity.ConfidenceLabel(), data.Maturity.Confidence*100)
+	}
+	for _, s := range data.Maturity.Signals {
+		if s.Known {
+			md += fmt.Sprintf("- %s: %d (%s)\n", s.Name, s.Score, s.Detail)
+		} else {
+			md += fmt.Sprintf("- %s: not checked\n", s.Name)
+		}
+	}
+	md += structureMarkdown(data.Files)
+
 	md += "\n## File Tree (Top 20)\n"
 	limit := 20
 	if len(data.FileTree) < limit {
@@ -46,3 +66,69 @@ func ExportMarkdown(data AnalysisResult, filename string) error {
 	_, err = file.WriteString(md)
 	return err
 }
+
+// structureMarkdown renders the files analysis as Markdown sections
+func structureMarkdown(s analyzer.FileStats) string {
+	if s.Files == 0 {
+		return ""
+	}
+
+	md := fmt.Sprintf("\n## Structure\n\n%d files in %d directories, %s\n", s.Files, s.Dirs, formatBytes(s.Bytes))
+
+	md += "\n### Community Files\n\n"
+	for _, c := range s.Community {
+		if c.Present() {
+			md += fmt.Sprintf("- [x] %s (`%s`)\n", c.Name, c.Path)
+		} else {
+			md += fmt.Sprintf("- [ ] %s\n", c.Name)
+		}
+	}
+
+	md += "\n### Extensions\n\n| Extension | Files | Size |\n|---|---:|---:|\n"
+	for i, e := range s.Extensions {
+		if i == 10 {
+			break
+		}
+		md += fmt.Sprintf("| %s | %d | %s |\n", e.Ext, e.Files, formatBytes(e.Bytes))
+	}
+
+	md += "\n### Top-Level Directories\n\n| Directory | Files | Size |\n|---|---:|---:|\n"
+	for i, d := range s.Directories {
+		if i == 10 {
+			break
+		}
+		md += fmt.Sprintf("| %s | %d | %s |\n", d.Dir, d.Files, formatBytes(d.Bytes))
+	}
+
+	if len(s.Tests) > 0 {
+		md += "\n### Tests\n\n| Language | Source | Tests | Ratio |\n|---|---:|---:|---:|\n"
+		for _, t := range s.Tests {
+			md += fmt.Sprintf("| %s | %d | %d | %.2f |\n", t.Language, t.Source, t.Tests, t.Ratio)
+		}
+	}
+
+	md += "\n### Largest Files\n\n"
+	for _, f := range s.Largest {
+		md += fmt.Sprintf("- `%s` (%s)\n", f.Path, formatBytes(int64(f.Size)))
+	}
+
+	if len(s.Vendored) > 0 {
+		md += "\n### Vendored or Generated\n\n"
+		for _, d := range s.Vendored {
+			md += fmt.Sprintf("- `%s/`\n", d)
+		}
+	}
+	if len(s.Binaries) > 0 {
+		md += "\n### Binary Files\n\n"
+		for _, f := range s.Binaries {
+			md += fmt.Sprintf("- `%s` (%s)\n", f.Path, formatBytes(int64(f.Size)))
+		}
+	}
+	if len(s.DeepPaths) > 0 {
+		md += fmt.Sprintf("\n### Deeply Nested Files (max depth %d)\n\n", s.MaxDepth)
+		for _, p := range s.DeepPaths {
+			md += fmt.Sprintf("- `%s`\n", p)
+		}
+	}
+	return md
+}
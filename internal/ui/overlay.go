@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// treeOverlay colour-codes tree nodes by one measure
+type treeOverlay int
+
+const (
+	overlayNone treeOverlay = iota
+	overlaySize
+	overlayChurn
+	overlayAge
+	overlayCount
+)
+
+// heatStyles run from cold to hot
+var heatStyles = []lipgloss.Style{
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#5F87AF")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF87")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F00")).Bold(true),
+}
+
+// shareBarWidth is how many cells the size overlay's bar takes
+const shareBarWidth = 8
+
+// needsActivity reports whether the overlay is drawn from commit data
+func (o treeOverlay) needsActivity() bool {
+	return o == overlayChurn || o == overlayAge
+}
+
+// overlayCell annotates node for the current overlay, returning the
+// annotation and its heat, or a heat of -1 when there is nothing to show
+func (m TreeModel) overlayCell(node *FileNode, now time.Time) (string, int) {
+	switch m.overlay {
+	case overlaySize:
+		share := 1.0
+		if node.parent != nil {
+			share = 0
+			if node.parent.Size > 0 {
+				share = float64(node.Size) / float64(node.parent.Size)
+			}
+		}
+		filled := int(share*shareBarWidth + 0.5)
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", shareBarWidth-filled)
+		return fmt.Sprintf("%s %5.1f%%", bar, share*100), sizeHeat(share)
+
+	case overlayChurn:
+		if m.activity == nil {
+			return "", -1
+		}
+		commits := 0
+		if f := m.activity.Paths[node.Path]; f != nil {
+			commits = f.Commits
+		}
+		// Files compete with the hottest file, directories with the window
+		scale := m.activity.MaxFile
+		if node.Type == "dir" {
+			scale = m.activity.Commits
+		}
+		label := fmt.Sprintf("%d commits", commits)
+		if commits == 1 {
+			label = "1 commit"
+		}
+		return label, churnHeat(commits, scale)
+
+	case overlayAge:
+		if m.activity == nil {
+			return "", -1
+		}
+		f := m.activity.Paths[node.Path]
+		if f == nil {
+			return "unchanged since " + m.activity.Since.Format("Jan 2006"), 0
+		}
+		days := int(now.Sub(f.LastChanged).Hours() / 24)
+		label := fmt.Sprintf("changed %dd ago", days)
+		if days == 0 {
+			label = "changed today"
+		}
+		return label, ageHeat(days)
+	}
+	return "", -1
+}
+
+func sizeHeat(share float64) int {
+	switch {
+	case share >= 0.5:
+		return 3
+	case share >= 0.2:
+		return 2
+	case share >= 0.05:
+		return 1
+	}
+	return 0
+}
+
+func churnHeat(commits, scale int) int {
+	if commits == 0 || scale == 0 {
+		return 0
+	}
+	ratio := float64(commits) / float64(scale)
+	switch {
+	case ratio >= 0.6:
+		return 3
+	case ratio >= 0.3:
+		return 2
+	}
+	return 1
+}
+
+// ageHeat is hottest for recent changes, so long-untouched code stands out
+// as cold
+func ageHeat(days int) int {
+	switch {
+	case days <= 7:
+		return 3
+	case days <= 30:
+		return 2
+	case days <= 90:
+		return 1
+	}
+	return 0
+}
+
+// overlayLine explains the current overlay
+func (m TreeModel) overlayLine() string {
+	var desc string
+	switch m.overlay {
+	case overlayNone:
+		return ""
+	case overlaySize:
+		desc = "size as a share of the parent directory"
+	case overlayChurn:
+		desc = "commits touching each path"
+	case overlayAge:
+		desc = "time since each path last changed"
+	}
+
+	line := InputStyle.Render("Overlay: ") + desc
+	if m.overlay.needsActivity() {
+		switch {
+		case m.activityErr != nil:
+			line += "  " + ErrorStyle.Render(m.activityErr.Error())
+		case m.activityLoading:
+			line += "  " + SubtleStyle.Render("loading commit history…")
+		case m.activity != nil:
+			note := fmt.Sprintf("since %s, %d commits", m.activity.Since.Format("Jan 2, 2006"), m.activity.Commits)
+			if m.activity.Partial {
+				note = fmt.Sprintf("latest %d commits since %s", m.activity.Commits, m.activity.Since.Format("Jan 2, 2006"))
+			}
+			line += "  " + SubtleStyle.Render(note)
+		}
+	}
+
+	legend := []string{"cold", "", "", "hot"}
+	for i, style := range heatStyles {
+		if legend[i] != "" {
+			legend[i] = style.Render(legend[i])
+		} else {
+			legend[i] = style.Render("■")
+		}
+	}
+	return line + "  " + strings.Join(legend, " ")
+}
@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxPreviewBytes is the largest file the preview pane fetches
+const maxPreviewBytes = 512 << 10
+
+// maxCachedPreviews bounds how many rendered previews are kept in memory
+const maxCachedPreviews = 32
+
+// Preview kinds
+const (
+	previewText      = "text"
+	previewImage     = "image"
+	previewBinary    = "binary"
+	previewTooLarge  = "too-large"
+	previewSubmodule = "submodule"
+)
+
+var imageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".bmp": true,
+	".webp": true, ".ico": true, ".tiff": true,
+}
+
+// filePreview is a file rendered for the preview pane
+type filePreview struct {
+	Path  string
+	SHA   string
+	Size  int64
+	Kind  string
+	Lines []string // highlighted source with line numbers, for text
+	Meta  []string // description, for everything else
+	Err   error
+}
+
+// previewLoadedMsg delivers a fetched preview
+type previewLoadedMsg struct {
+	preview *filePreview
+}
+
+// previewCache keeps rendered previews by blob SHA and file name, dropping
+// the oldest once full. Blobs never change, so entries never go stale; the
+// name is part of the key because it picks the highlighter.
+type previewCache struct {
+	entries map[string]*filePreview
+	order   []string
+}
+
+func newPreviewCache() *previewCache {
+	return &previewCache{entries: map[string]*filePreview{}}
+}
+
+func previewKey(sha, filePath string) string {
+	return sha + "\x00" + path.Base(filePath)
+}
+
+// get returns a copy of the cached preview carrying node's path, which may
+// differ from the path the blob was first rendered for
+func (c *previewCache) get(node *FileNode) *filePreview {
+	p, ok := c.entries[previewKey(node.SHA, node.Path)]
+	if !ok {
+		return nil
+	}
+	cp := *p
+	cp.Path = node.Path
+	return &cp
+}
+
+func (c *previewCache) put(p *filePreview) {
+	if p.SHA == "" || p.Err != nil {
+		return
+	}
+	key := previewKey(p.SHA, p.Path)
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	if len(c.order) == maxCachedPreviews {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = p
+	c.order = append(c.order, key)
+}
+
+// previewFor returns a preview that needs no fetch (cached, too large or a
+// submodule), or nil when the blob has to be fetched
+func previewFor(node *FileNode, cache *previewCache) *filePreview {
+	if p := cache.get(node); p != nil {
+		return p
+	}
+
+	p := &filePreview{Path: node.Path, SHA: node.SHA, Size: node.Size}
+	switch {
+	case node.Type == "submodule":
+		p.Kind = previewSubmodule
+		p.Meta = []string{"Git submodule", "Commit: " + node.SHA}
+	case node.Size > maxPreviewBytes:
+		p.Kind = previewTooLarge
+		p.Meta = []string{
+			fmt.Sprintf("%s is too large to preview", formatBytes(node.Size)),
+			fmt.Sprintf("Limit: %s", formatBytes(maxPreviewBytes)),
+		}
+	default:
+		return nil
+	}
+	return p
+}
+
+// loadPreview fetches a blob and renders it
+func loadPreview(client *github.Client, target analysis.Target, node *FileNode) tea.Cmd {
+	filePath, sha, size := node.Path, node.SHA, node.Size
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), treeFetchTimeout)
+		defer cancel()
+
+		p := &filePreview{Path: filePath, SHA: sha, Size: size}
+		blob, err := client.GetBlob(ctx, target.Owner, target.Name, sha)
+		if err != nil {
+			p.Err = err
+			return previewLoadedMsg{p}
+		}
+		data, err := blob.Decode()
+		if err != nil {
+			p.Err = err
+			return previewLoadedMsg{p}
+		}
+		renderPreview(p, data)
+		return previewLoadedMsg{p}
+	}
+}
+
+// renderPreview fills in p from the file's bytes
+func renderPreview(p *filePreview, data []byte) {
+	p.Size = int64(len(data))
+	ext := strings.ToLower(path.Ext(p.Path))
+
+	if imageExts[ext] {
+		p.Kind = previewImage
+		p.Meta = []string{fmt.Sprintf("Image, %s", formatBytes(p.Size))}
+		if cfg, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			p.Meta = []string{
+				fmt.Sprintf("%s image", strings.ToUpper(format)),
+				fmt.Sprintf("Dimensions: %d × %d", cfg.Width, cfg.Height),
+				fmt.Sprintf("Size: %s", formatBytes(p.Size)),
+			}
+		}
+		return
+	}
+
+	if isBinary(data) {
+		p.Kind = previewBinary
+		p.Meta = []string{
+			"Binary file",
+			fmt.Sprintf("Type: %s", http.DetectContentType(data)),
+			fmt.Sprintf("Size: %s", formatBytes(p.Size)),
+		}
+		return
+	}
+
+	p.Kind = previewText
+	p.Lines = highlight(p.Path, string(data))
+}
+
+// isBinary uses git's heuristic: a NUL byte near the start, or text that
+// is not valid UTF-8
+func isBinary(data []byte) bool {
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+		// Drop a character the cut split in two, so it is not mistaken
+		// for invalid UTF-8
+		for i := len(head) - 1; i >= 0 && i >= len(head)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(head[i]) {
+				if !utf8.FullRune(head[i:]) {
+					head = head[:i]
+				}
+				break
+			}
+		}
+	}
+	return bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(head)
+}
+
+// highlight renders source with ANSI colours and line numbers. Lines are
+// formatted one at a time so every line carries its own colour codes.
+func highlight(filePath, src string) []string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+
+	lexer := lexers.Match(path.Base(filePath))
+	if lexer == nil {
+		lexer = lexers.Analyse(src)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+	formatter := formatters.Get("terminal256")
+	style := styles.Get("monokai")
+
+	var lines []string
+	iterator, err := lexer.Tokenise(nil, src)
+	if err != nil {
+		lines = strings.Split(src, "\n")
+	} else {
+		for _, tokens := range chroma.SplitTokensIntoLines(iterator.Tokens()) {
+			if n := len(tokens); n > 0 {
+				tokens[n-1].Value = strings.TrimSuffix(tokens[n-1].Value, "\n")
+			}
+			var buf bytes.Buffer
+			if err := formatter.Format(&buf, style, chroma.Literator(tokens...)); err != nil {
+				lines = append(lines, "")
+				continue
+			}
+			lines = append(lines, buf.String())
+		}
+	}
+	// A trailing newline does not start another line
+	if n := len(lines); n > 0 && strings.HasSuffix(src, "\n") && ansi.Strip(lines[n-1]) == "" {
+		lines = lines[:n-1]
+	}
+
+	width := len(fmt.Sprint(len(lines)))
+	for i, line := range lines {
+		lines[i] = SubtleStyle.Render(fmt.Sprintf("%*d │ ", width, i+1)) + line
+	}
+	return lines
+}
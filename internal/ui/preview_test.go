@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	// "é" is two bytes; 7999 ASCII bytes put one across the 8000 byte cut
+	straddling := append(bytes.Repeat([]byte("a"), 7999), strings.Repeat("é", 10)...)
+	// "€" is three bytes; the cut leaves two of them
+	straddling3 := append(bytes.Repeat([]byte("a"), 7998), strings.Repeat("€", 10)...)
+	broken := append(bytes.Repeat([]byte("a"), 7000), 0xff, 'b')
+
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"empty", nil, false},
+		{"ascii", []byte("package main\n"), false},
+		{"multibyte", []byte("héllo, 世界"), false},
+		{"nul byte", []byte("abc\x00def"), true},
+		{"invalid utf-8", broken, true},
+		{"two byte rune across the cut", straddling, false},
+		{"three byte rune across the cut", straddling3, false},
+		{"invalid byte at the cut", append(bytes.Repeat([]byte("a"), 7999), 0xff, 'b'), true},
+		{"nul after the cut", append(bytes.Repeat([]byte("a"), 9000), 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBinary(tt.data); got != tt.want {
+				t.Errorf("isBinary = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewCacheUsesNodePath(t *testing.T) {
+	cache := newPreviewCache()
+	cache.put(&filePreview{Path: "a/main.go", SHA: "abc", Kind: previewText, Lines: []string{"x"}})
+
+	p := previewFor(&FileNode{Path: "b/main.go", SHA: "abc"}, cache)
+	if p == nil {
+		t.Fatal("same blob and file name missed the cache")
+	}
+	if p.Path != "b/main.go" {
+		t.Errorf("Path = %q, want the requested node's path", p.Path)
+	}
+	if again := cache.get(&FileNode{Path: "a/main.go", SHA: "abc"}); again.Path != "a/main.go" {
+		t.Errorf("cached entry was changed to %q", again.Path)
+	}
+
+	if p := previewFor(&FileNode{Path: "a/main.txt", SHA: "abc"}, cache); p != nil {
+		t.Error("a different file name reused a preview highlighted for main.go")
+	}
+}
+
+func TestPreviewCacheEviction(t *testing.T) {
+	cache := newPreviewCache()
+	cache.put(&filePreview{Path: "f", SHA: "failed", Err: errors.New("boom")})
+	if cache.get(&FileNode{Path: "f", SHA: "failed"}) != nil {
+		t.Error("failed preview was cached")
+	}
+	for i := 0; i <= maxCachedPreviews; i++ {
+		cache.put(&filePreview{Path: "f", SHA: strings.Repeat("x", i+1)})
+	}
+	if cache.get(&FileNode{Path: "f", SHA: "x"}) != nil {
+		t.Error("oldest entry was not evicted")
+	}
+	if cache.get(&FileNode{Path: "f", SHA: "xx"}) == nil {
+		t.Error("second entry was evicted")
+	}
+}
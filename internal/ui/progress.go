@@ -1,64 +1,97 @@
 package ui
 
 import (
-	tea "github.com/charmbracelet/bubbletea"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // ProgressStage represents a step in the analysis process
 type ProgressStage struct {
 	Name       string
+	Fetch      analysis.Fetch // Empty for the final metrics stage
 	IsComplete bool
 	IsActive   bool
+	Pages      int
+	Bytes      int64
+	Err        error
 }
 
-// ProgressTracker manages multi-step analysis progress
+// ProgressTracker follows the stages of one repository's analysis as
+// events stream in from the analysis pipeline
 type ProgressTracker struct {
+	Target    string
 	stages    []ProgressStage
-	current   int
 	startTime time.Time
 }
 
-// ProgressUpdateMsg is sent to update progress
+// ProgressUpdateMsg carries one pipeline event to the model
 type ProgressUpdateMsg struct {
-	StageIndex int
-	IsComplete bool
+	Event  analysis.Event
+	events <-chan analysis.Event // Source to resubscribe to
 }
 
-// NewProgressTracker creates a tracker with default analysis stages
-func NewProgressTracker() *ProgressTracker {
-	return &ProgressTracker{
-		stages: []ProgressStage{
-			{Name: "🔗 Fetching repository data", IsComplete: false, IsActive: true},
-			{Name: "📝 Analyzing commits", IsComplete: false, IsActive: false},
-			{Name: "👥 Analyzing contributors", IsComplete: false, IsActive: false},
-			{Name: "🗣️  Analyzing languages", IsComplete: false, IsActive: false},
-			{Name: "📊 Computing metrics", IsComplete: false, IsActive: false},
-			{Name: "✅ Analysis complete", IsComplete: false, IsActive: false},
-		},
-		current:   0,
-		startTime: time.Now(),
+// stageNames labels each fetch on the loading screen
+var stageNames = map[analysis.Fetch]string{
+	analysis.FetchRepo:           "🔗 Fetching repository data",
+	analysis.FetchCommits:        "📝 Analyzing commits",
+	analysis.FetchContributors:   "👥 Analyzing contributors",
+	analysis.FetchLanguages:      "🗣️  Analyzing languages",
+	analysis.FetchTree:           "🌳 Fetching file tree",
+	analysis.FetchReleases:       "🏷️  Fetching releases",
+	analysis.FetchTags:           "🔖 Fetching tags",
+	analysis.FetchIssues:         "🐛 Fetching issues",
+	analysis.FetchComments:       "💬 Fetching issue comments",
+	analysis.FetchPulls:          "🔀 Fetching pull requests",
+	analysis.FetchPullDetails:    "🔍 Sampling pull request reviews",
+	analysis.FetchReviewComments: "🗨️  Fetching review comments",
+	analysis.FetchCommitFiles:    "📂 Sampling commit files",
+	analysis.FetchBranch:         "🛡️  Checking branch protection",
+	analysis.FetchWorkflows:      "⚙️  Reading workflows",
+}
+
+// NewProgressTracker creates a tracker with one stage per fetch the run
+// makes plus a final metrics stage
+func NewProgressTracker(target string, fetches []analysis.Fetch) *ProgressTracker {
+	pt := &ProgressTracker{Target: target, startTime: time.Now()}
+	for _, f := range fetches {
+		pt.stages = append(pt.stages, ProgressStage{Name: stageNames[f], Fetch: f})
 	}
+	pt.stages = append(pt.stages, ProgressStage{Name: "📊 Computing metrics"})
+	return pt
 }
 
-// NextStage moves to the next analysis stage
-func (pt *ProgressTracker) NextStage() {
-	if pt.current < len(pt.stages) {
-		pt.stages[pt.current].IsComplete = true
-		pt.stages[pt.current].IsActive = false
-		pt.current++
-		if pt.current < len(pt.stages) {
-			pt.stages[pt.current].IsActive = true
+// Apply updates the tracker with an event from the pipeline
+func (pt *ProgressTracker) Apply(ev analysis.Event) {
+	for i := range pt.stages {
+		stage := &pt.stages[i]
+		if stage.Fetch != ev.Stage {
+			continue
+		}
+
+		stage.Pages = ev.Pages
+		stage.Bytes = ev.Bytes
+		switch ev.Kind {
+		case analysis.StageStarted, analysis.PageFetched:
+			stage.IsActive = true
+		case analysis.StageFinished:
+			stage.IsActive = false
+			stage.IsComplete = true
+			stage.Err = ev.Err
 		}
 	}
-}
 
-// GetCurrentStage returns the current stage information
-func (pt *ProgressTracker) GetCurrentStage() ProgressStage {
-	if pt.current < len(pt.stages) {
-		return pt.stages[pt.current]
+	// Metrics are computed once every fetch has finished
+	last := &pt.stages[len(pt.stages)-1]
+	last.IsActive = true
+	for _, stage := range pt.stages[:len(pt.stages)-1] {
+		if !stage.IsComplete {
+			last.IsActive = false
+		}
 	}
-	return ProgressStage{Name: "Complete", IsComplete: true, IsActive: false}
 }
 
 // GetAllStages returns all stages with their status
@@ -113,9 +146,73 @@ func (pt *ProgressTracker) GetElapsedTime() time.Duration {
 	return time.Since(pt.startTime)
 }
 
+// ETA extrapolates the remaining time from the pace of completed stages.
+// ok is false until at least one stage has finished.
+func (pt *ProgressTracker) ETA() (eta time.Duration, ok bool) {
+	completed, total := pt.GetProgress()
+	if completed == 0 {
+		return 0, false
+	}
+	perStage := pt.GetElapsedTime() / time.Duration(completed)
+	return perStage * time.Duration(total-completed), true
+}
+
+// View renders the stage list with per-stage page counts and errors
+func (pt *ProgressTracker) View() string {
+	var sb strings.Builder
+	for _, stage := range pt.stages {
+		prefix := "⏳ "
+		if stage.Err != nil {
+			prefix = "❌ "
+		} else if stage.IsComplete {
+			prefix = "✅ "
+		} else if stage.IsActive {
+			prefix = "⚙️  "
+		}
+
+		line := prefix + stage.Name
+		if stage.Pages > 0 {
+			line += SubtleStyle.Render(fmt.Sprintf("  %d page(s), %s", stage.Pages, formatBytes(stage.Bytes)))
+		}
+		if stage.Err != nil {
+			line += "  " + ErrorStyle.Render(stage.Err.Error())
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString("\n" + pt.GetProgressBar(30))
+	sb.WriteString(fmt.Sprintf("⏱️  %ds elapsed", int(pt.GetElapsedTime().Seconds())))
+	if eta, ok := pt.ETA(); ok {
+		sb.WriteString(fmt.Sprintf(" • ~%ds left", int(eta.Seconds())))
+	}
+	return sb.String()
+}
+
+// waitForProgress delivers the next event from events as a
+// ProgressUpdateMsg; the model calls it again after each one
+func waitForProgress(events <-chan analysis.Event) tea.Cmd {
+	return func() tea.Msg {
+		ev, ok := <-events
+		if !ok {
+			return nil
+		}
+		return ProgressUpdateMsg{Event: ev, events: events}
+	}
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
 // TickProgressCmd returns a command that ticks every 150ms for smoother skeleton animation
 func TickProgressCmd() tea.Cmd {
 	return tea.Tick(time.Millisecond*150, func(t time.Time) tea.Msg {
 		return struct{}{} // Progress tick message
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// treeQuery is a parsed tree search. Free text is matched fuzzily against
+// full paths; the other terms filter which files are shown:
+//
+//	ext:go,md     only these extensions
+//	size>100k     only files larger (or size<1m, smaller) than a size
+//	changed:30d   only files changed in the last 30 days
+type treeQuery struct {
+	Text        string
+	Exts        map[string]bool
+	MinSize     int64 // smallest size shown
+	MaxSize     int64 // first size hidden, 0 means no limit
+	ChangedDays int
+}
+
+// filtering reports whether the query hides any files
+func (q treeQuery) filtering() bool {
+	return len(q.Exts) > 0 || q.MinSize > 0 || q.MaxSize > 0 || q.ChangedDays > 0
+}
+
+// empty reports whether the query neither matches nor filters anything
+func (q treeQuery) empty() bool {
+	return q.Text == "" && !q.filtering()
+}
+
+// changedCutoff is the start of the "changed in" window
+func (q treeQuery) changedCutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -q.ChangedDays)
+}
+
+// parseTreeQuery splits a search into free text and filters. Terms that
+// look like filters but do not parse are reported as an error.
+func parseTreeQuery(input string) (treeQuery, error) {
+	var q treeQuery
+	var text []string
+
+	for _, term := range strings.Fields(input) {
+		lower := strings.ToLower(term)
+		switch {
+		case strings.HasPrefix(lower, "ext:"):
+			if q.Exts == nil {
+				q.Exts = map[string]bool{}
+			}
+			for _, ext := range strings.Split(lower[len("ext:"):], ",") {
+				if ext = strings.TrimPrefix(ext, "."); ext != "" {
+					q.Exts["."+ext] = true
+				}
+			}
+		case strings.HasPrefix(lower, "size>"), strings.HasPrefix(lower, "size<"):
+			n, err := parseSize(lower[len("size>"):])
+			if err != nil {
+				return q, fmt.Errorf("%s: %w", term, err)
+			}
+			switch {
+			case lower[4] == '>':
+				q.MinSize = n + 1
+			case n == 0:
+				return q, fmt.Errorf("%s: no file is smaller than 0 bytes", term)
+			default:
+				q.MaxSize = n
+			}
+		case strings.HasPrefix(lower, "changed:"):
+			days, err := parseDays(lower[len("changed:"):])
+			if err != nil {
+				return q, fmt.Errorf("%s: %w", term, err)
+			}
+			q.ChangedDays = days
+		default:
+			text = append(text, term)
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// parseSize reads sizes like 500, 10k, 1.5mb
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSuffix(s, "b")
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"):
+		mult, s = 1<<10, strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "m"):
+		mult, s = 1<<20, strings.TrimSuffix(s, "m")
+	case strings.HasSuffix(s, "g"):
+		mult, s = 1<<30, strings.TrimSuffix(s, "g")
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * mult), nil
+}
+
+// parseDays reads durations like 30, 30d, 2w, 6m or 1y as a day count
+func parseDays(s string) (int, error) {
+	unit := 1
+	switch {
+	case strings.HasSuffix(s, "d"):
+		s = strings.TrimSuffix(s, "d")
+	case strings.HasSuffix(s, "w"):
+		unit, s = 7, strings.TrimSuffix(s, "w")
+	case strings.HasSuffix(s, "m"):
+		unit, s = 30, strings.TrimSuffix(s, "m")
+	case strings.HasSuffix(s, "y"):
+		unit, s = 365, strings.TrimSuffix(s, "y")
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid number of days %q", s)
+	}
+	return n * unit, nil
+}
+
+// keepFile reports whether a file passes the extension and size filters.
+// The changed filter needs commit data and is applied separately.
+func (q treeQuery) keepFile(node *FileNode) bool {
+	if node.Type == "dir" {
+		return false
+	}
+	if len(q.Exts) > 0 && !q.Exts[strings.ToLower(path.Ext(node.Name))] {
+		return false
+	}
+	if node.Size < q.MinSize {
+		return false
+	}
+	if q.MaxSize > 0 && node.Size >= q.MaxSize {
+		return false
+	}
+	return true
+}
+
+// fuzzyMatch matches pattern as a case-insensitive subsequence of target,
+// returning a score (higher is better) and the byte offsets of the matched
+// characters. Spaces in the pattern are ignored. Matching runs from the end
+// so that characters land in the file name rather than its directories.
+func fuzzyMatch(pattern, target string) (int, []int, bool) {
+	pat := []rune(strings.ToLower(strings.ReplaceAll(pattern, " ", "")))
+	if len(pat) == 0 {
+		return 0, nil, false
+	}
+
+	base := strings.LastIndex(target, "/") + 1
+
+	positions := make([]int, len(pat))
+	p := len(pat) - 1
+	for i := len(target); i > 0 && p >= 0; {
+		r, size := utf8.DecodeLastRuneInString(target[:i])
+		i -= size
+		if unicode.ToLower(r) == pat[p] {
+			positions[p] = i
+			p--
+		}
+	}
+	if p >= 0 {
+		return 0, nil, false
+	}
+
+	score := 0
+	for k, pos := range positions {
+		score++
+		if pos >= base {
+			score += 2
+		}
+		r, _ := utf8.DecodeRuneInString(target[pos:])
+		prev, size := utf8.DecodeLastRuneInString(target[:pos])
+		if pos == 0 || strings.ContainsRune("/_-. ", prev) || (unicode.IsUpper(r) && unicode.IsLower(prev)) {
+			score += 4
+		}
+		if k > 0 && positions[k-1]+size == pos {
+			score += 3
+		}
+	}
+	// Prefer shorter paths among equal matches
+	score = score*100 - len(target)
+	return score, positions, true
+}
+
+// treeMatch is a node matched by a search, with the matched offsets
+// relative to the node's name
+type treeMatch struct {
+	node      *FileNode
+	score     int
+	positions []int
+}
+
+// searchTree collects the loaded nodes matching q.Text, best first. Nodes
+// hidden by keep are skipped.
+func searchTree(root *FileNode, text string, keep func(*FileNode) bool) []treeMatch {
+	var matches []treeMatch
+	var walk func(node *FileNode)
+	walk = func(node *FileNode) {
+		for _, child := range node.Children {
+			if keep != nil && !keep(child) {
+				continue
+			}
+			if score, positions, ok := fuzzyMatch(text, child.Path); ok {
+				// Highlighting only covers the name; its offset is where it
+				// starts in the path
+				offset := len(child.Path) - len(child.Name)
+				var inName []int
+				for _, pos := range positions {
+					if pos >= offset {
+						inName = append(inName, pos-offset)
+					}
+				}
+				matches = append(matches, treeMatch{node: child, score: score, positions: inName})
+			}
+			walk(child)
+		}
+	}
+	walk(root)
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].node.Path < matches[j].node.Path
+	})
+	return matches
+}
+
+// highlightName renders name with the characters at positions picked out
+func highlightName(name string, positions []int, base, hl func(...string) string) string {
+	if len(positions) == 0 {
+		return base(name)
+	}
+	marked := map[int]bool{}
+	for _, pos := range positions {
+		marked[pos] = true
+	}
+
+	var b strings.Builder
+	start := 0
+	for i := range name {
+		if marked[i] != marked[start] {
+			b.WriteString(renderRun(name[start:i], marked[start], base, hl))
+			start = i
+		}
+	}
+	b.WriteString(renderRun(name[start:], marked[start], base, hl))
+	return b.String()
+}
+
+func renderRun(s string, marked bool, base, hl func(...string) string) string {
+	if marked {
+		return hl(s)
+	}
+	return base(s)
+}
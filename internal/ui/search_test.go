@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		target    string
+		wantOK    bool
+		positions []int
+	}{
+		{"empty pattern", "", "main.go", false, nil},
+		{"only spaces", "  ", "main.go", false, nil},
+		{"no match", "xyz", "cmd/main.go", false, nil},
+		{"out of order", "gm", "main.go", false, nil},
+		{"subsequence lands in the name", "mg", "cmd/main.go", true, []int{4, 9}},
+		{"case insensitive", "MAIN", "cmd/main.go", true, []int{4, 5, 6, 7}},
+		{"spaces ignored", "ma in", "cmd/main.go", true, []int{4, 5, 6, 7}},
+		{"two byte runes", "üb", "dir/über.txt", true, []int{4, 6}},
+		{"upper case multibyte pattern", "Ü", "dir/über.txt", true, []int{4}},
+		{"three byte runes", "日本", "docs/日本語.md", true, []int{5, 8}},
+		{"multibyte directory", "ar", "日本/a.rs", true, []int{7, 9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := fuzzyMatch(tt.pattern, tt.target)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(positions, tt.positions) {
+				t.Errorf("positions = %v, want %v", positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatchRanking(t *testing.T) {
+	// Each pair is better, worse for the pattern
+	tests := []struct {
+		pattern       string
+		better, worse string
+	}{
+		{"util", "src/util.go", "util/a.go"},
+		{"main", "main.go", "domain.go"},
+		{"rc", "readme_config.md", "pyrc.txt"},
+		{"main", "main.go", "cmd/main.go"},
+		{"tf", "TruckFactor.go", "testfile.go"},
+		{"ab", "x/ab.go", "xa/b.go"},
+	}
+	for _, tt := range tests {
+		better, _, ok1 := fuzzyMatch(tt.pattern, tt.better)
+		worse, _, ok2 := fuzzyMatch(tt.pattern, tt.worse)
+		if !ok1 || !ok2 {
+			t.Errorf("%q: %q or %q did not match", tt.pattern, tt.better, tt.worse)
+			continue
+		}
+		if better <= worse {
+			t.Errorf("%q: %q scored %d, not above %q at %d", tt.pattern, tt.better, better, tt.worse, worse)
+		}
+	}
+}
+
+func TestParseTreeQuery(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    treeQuery
+		wantErr string
+	}{
+		{input: "", want: treeQuery{}},
+		{input: "  main   go ", want: treeQuery{Text: "main go"}},
+		{input: "ext:go,.MD handler", want: treeQuery{Text: "handler", Exts: map[string]bool{".go": true, ".md": true}}},
+		{input: "ext:", want: treeQuery{Exts: map[string]bool{}}},
+		{input: "size>10k", want: treeQuery{MinSize: 10<<10 + 1}},
+		{input: "SIZE<1.5MB", want: treeQuery{MaxSize: 3 << 19}},
+		{input: "size>0", want: treeQuery{MinSize: 1}},
+		{input: "size>1k size<2k", want: treeQuery{MinSize: 1<<10 + 1, MaxSize: 2 << 10}},
+		{input: "changed:2w readme", want: treeQuery{Text: "readme", ChangedDays: 14}},
+		{input: "size<0", wantErr: "no file is smaller"},
+		{input: "size>", wantErr: "invalid size"},
+		{input: "size<-1", wantErr: "invalid size"},
+		{input: "size>10x", wantErr: "invalid size"},
+		{input: "changed:0", wantErr: "invalid number of days"},
+		{input: "changed:soon", wantErr: "invalid number of days"},
+		{input: "size=10", want: treeQuery{Text: "size=10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseTreeQuery(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"500", 500, false},
+		{"500b", 500, false},
+		{"10k", 10 << 10, false},
+		{"10kb", 10 << 10, false},
+		{"1.5m", 3 << 19, false},
+		{"2g", 2 << 30, false},
+		{"", 0, true},
+		{"k", 0, true},
+		{"-1", 0, true},
+		{"1t", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseSize(%q) = %d, %v, want %d (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"30", 30, false},
+		{"30d", 30, false},
+		{"2w", 14, false},
+		{"6m", 180, false},
+		{"1y", 365, false},
+		{"0", 0, true},
+		{"-3d", 0, true},
+		{"d", 0, true},
+		{"1.5w", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDays(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseDays(%q) = %d, %v, want %d (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestKeepFileSizeBounds(t *testing.T) {
+	q, err := parseTreeQuery("size>100 size<200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for size, want := range map[int64]bool{99: false, 100: false, 101: true, 199: true, 200: false} {
+		if got := q.keepFile(&FileNode{Name: "f", Type: "file", Size: size}); got != want {
+			t.Errorf("%d byte file kept = %v, want %v", size, got, want)
+		}
+	}
+	if q.keepFile(&FileNode{Name: "d", Type: "dir", Size: 150}) {
+		t.Error("directories are kept by size")
+	}
+	if q, _ := parseTreeQuery("size>0"); q.keepFile(&FileNode{Name: "empty", Type: "file"}) {
+		t.Error("size>0 kept an empty file")
+	}
+}
@@ -82,9 +82,12 @@ func GetFileTreeShortcuts() []KeyboardShortcut {
 		{Key: "↑/↓ or j/k", Description: "Navigate files"},
 		{Key: "→ or l", Description: "Expand folder"},
 		{Key: "← or h", Description: "Collapse folder"},
-		{Key: "Enter", Description: "View file details"},
-		{Key: "Ctrl+S", Description: "Search files"},
-		{Key: "ESC", Description: "Go back"},
+		{Key: "Enter", Description: "Preview file"},
+		{Key: "PgUp/PgDn", Description: "Scroll preview"},
+		{Key: "/", Description: "Search paths (ext:go size>10k changed:30d)"},
+		{Key: "n/N", Description: "Next/Previous match"},
+		{Key: "o", Description: "Cycle size/churn/age overlay"},
+		{Key: "ESC", Description: "Clear search / Go back"},
 	}
 }
 
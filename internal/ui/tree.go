@@ -1,73 +1,157 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // FileNode represents a file or directory in the repository
 type FileNode struct {
 	Name     string
-	Type     string // "file" or "dir"
+	Type     string // "file", "dir" or "submodule"
 	Path     string
-	Size     int64
+	SHA      string
+	Size     int64 // for directories, the total of everything loaded below
 	Children []*FileNode
 	Expanded bool
+	// Loaded is false for directories whose children were cut from a
+	// truncated listing and have not been fetched yet
+	Loaded bool
+	parent *FileNode
+}
+
+// treeFetchTimeout bounds loading one directory of a truncated tree
+const treeFetchTimeout = 30 * time.Second
+
+// subtreeLoadedMsg carries one directory level fetched for a truncated tree
+type subtreeLoadedMsg struct {
+	node    *FileNode
+	sha     string // the directory's tree, once looked up
+	entries []github.TreeEntry
+	err     error
 }
 
 // TreeModel represents the file tree view
 type TreeModel struct {
-	root        *FileNode
-	cursor      int
-	visibleList []*FileNode
-	width       int
-	height      int
-	Done        bool
+	root         *FileNode
+	cursor       int
+	visibleList  []*FileNode
+	visibleDepth []int
+	width        int
+	height       int
+	Done         bool
 	SelectedPath string
+
+	client    *github.Client
+	target    analysis.Target
+	truncated bool
+	loading   map[*FileNode]bool
+	err       error
+
+	// preview is the file shown in the right-hand pane, nil when closed
+	preview        *filePreview
+	previewScroll  int
+	previewLoading string
+	previews       *previewCache
+
+	// searching is true while a query is being typed. The applied query
+	// highlights and jumps between its matches and hides files failing
+	// its filters.
+	searching   bool
+	searchInput string
+	query       treeQuery
+	queryErr    error
+	matches     []treeMatch
+	matchPos    int
+	highlights  map[*FileNode][]int
+	kept        map[*FileNode]bool // nil unless the query filters
+
+	// overlay colour-codes nodes by size, churn or age
+	overlay treeOverlay
+
+	// activity is which paths commits touched, loaded for the "changed"
+	// filter and the churn and age overlays. windowStart is where the
+	// analysis' commit window begins.
+	windowStart     time.Time
+	commitFiles     []github.Commit
+	activity        *pathActivity
+	activityLoading bool
+	activityErr     error
 }
 
-func NewTreeModel(result *AnalysisResult) TreeModel {
+// matchStyle picks out the characters a search matched
+var matchStyle = InputStyle.Underline(true)
+
+func NewTreeModel(result *AnalysisResult, client *github.Client) TreeModel {
 	var root *FileNode
+	m := TreeModel{client: client, loading: map[*FileNode]bool{}, previews: newPreviewCache()}
 	if result != nil {
 		root = BuildFileTree(*result)
+		m.truncated = result.TreeTruncated
+		m.commitFiles = result.CommitFiles
+		m.windowStart = time.Now().AddDate(-1, 0, 0)
+		for _, c := range result.Commits {
+			if date := c.Commit.Author.Date; !date.IsZero() && date.Before(m.windowStart) {
+				m.windowStart = date
+			}
+		}
+		if result.Repo != nil {
+			m.target, _ = analysis.ParseTarget(result.Repo.FullName)
+		}
 	} else {
 		root = &FileNode{
 			Name:     "repository",
 			Type:     "dir",
-			Path:     "/",
 			Children: []*FileNode{},
+			Loaded:   true,
 		}
 	}
 
-	m := TreeModel{
-		root: root,
-	}
+	root.Expanded = true
+	m.root = root
 	m.updateVisibleList()
 	return m
 }
 
 func (m *TreeModel) updateVisibleList() {
-	m.visibleList = []*FileNode{}
+	m.visibleList = m.visibleList[:0]
+	m.visibleDepth = m.visibleDepth[:0]
 	m.addVisibleNodes(m.root, 0)
+	if m.cursor >= len(m.visibleList) {
+		m.cursor = len(m.visibleList) - 1
+	}
 }
 
 func (m *TreeModel) addVisibleNodes(node *FileNode, depth int) {
-	if node == m.root {
-		m.visibleList = append(m.visibleList, node)
-	} else {
-		m.visibleList = append(m.visibleList, node)
+	if m.kept != nil && !m.kept[node] {
+		return
 	}
+	m.visibleList = append(m.visibleList, node)
+	m.visibleDepth = append(m.visibleDepth, depth)
 
-	if node.Expanded && len(node.Children) > 0 {
+	if node.Expanded {
 		for _, child := range node.Children {
 			m.addVisibleNodes(child, depth+1)
 		}
 	}
 }
 
-func (m TreeModel) Init() tea.Cmd { return nil }
+// Init loads the root level when the recursive listing was truncated
+func (m TreeModel) Init() tea.Cmd {
+	if !m.root.Loaded {
+		return m.loadSubtree(m.root)
+	}
+	return nil
+}
 
 func (m TreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -75,8 +159,55 @@ func (m TreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+	case subtreeLoadedMsg:
+		delete(m.loading, msg.node)
+		if msg.err != nil {
+			m.err = msg.err
+			break
+		}
+		msg.node.SHA = msg.sha
+		mergeSubtree(msg.node, msg.entries)
+		if m.query.empty() {
+			m.updateVisibleList()
+		} else {
+			m.refilter()
+		}
+
+	case previewLoadedMsg:
+		m.previews.put(msg.preview)
+		// Ignore files the user has moved away from
+		if msg.preview.Path == m.previewLoading {
+			m.preview, m.previewScroll, m.previewLoading = msg.preview, 0, ""
+		}
+
+	case activityLoadedMsg:
+		m.activityLoading = false
+		m.activity, m.activityErr = msg.activity, msg.err
+		var cmd tea.Cmd
+		if msg.err == nil {
+			// The query or overlay may have moved on to a longer window
+			cmd = m.ensureActivity()
+		}
+		if m.query.ChangedDays > 0 {
+			m.refilter()
+		}
+		return m, cmd
+
 	case tea.KeyMsg:
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+
 		switch msg.String() {
+		case "/":
+			m.searching = true
+		case "o":
+			m.overlay = (m.overlay + 1) % overlayCount
+			return m, m.ensureActivity()
+		case "n":
+			m.jumpToMatch(m.matchPos + 1)
+		case "N":
+			m.jumpToMatch(m.matchPos - 1)
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
@@ -88,25 +219,61 @@ func (m TreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "right", "l":
 			if m.cursor < len(m.visibleList) {
 				node := m.visibleList[m.cursor]
-				if node.Type == "dir" && len(node.Children) > 0 {
+				if node.Type == "dir" {
 					node.Expanded = true
 					m.updateVisibleList()
+					if !node.Loaded {
+						return m, m.loadSubtree(node)
+					}
 				}
 			}
 		case "left", "h":
 			if m.cursor < len(m.visibleList) {
 				node := m.visibleList[m.cursor]
-				if node.Type == "dir" && node.Expanded {
+				if node.Type == "dir" && node.Expanded && node != m.root {
 					node.Expanded = false
 					m.updateVisibleList()
+				} else if node.parent != nil {
+					// Jump to the enclosing directory
+					for i, n := range m.visibleList {
+						if n == node.parent {
+							m.cursor = i
+							break
+						}
+					}
 				}
 			}
+		case "pgdown", "ctrl+d":
+			if m.preview != nil {
+				m.previewScroll = min(m.previewScroll+m.previewRows(), max(len(m.preview.Lines)-1, 0))
+			}
+		case "pgup", "ctrl+u":
+			if m.preview != nil {
+				m.previewScroll = max(m.previewScroll-m.previewRows(), 0)
+			}
 		case "enter":
 			if m.cursor < len(m.visibleList) {
-				m.SelectedPath = m.visibleList[m.cursor].Path
-				m.Done = true
+				node := m.visibleList[m.cursor]
+				m.SelectedPath = node.Path
+				if node.Type == "dir" {
+					node.Expanded = !node.Expanded
+					m.updateVisibleList()
+					if node.Expanded && !node.Loaded {
+						return m, m.loadSubtree(node)
+					}
+					break
+				}
+				return m, m.openPreview(node)
 			}
 		case "esc":
+			if m.searchInput != "" {
+				m.clearSearch()
+				break
+			}
+			if m.preview != nil || m.previewLoading != "" {
+				m.preview, m.previewLoading = nil, ""
+				break
+			}
 			m.Done = true
 		}
 	}
@@ -114,33 +281,348 @@ func (m TreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateSearch edits the query being typed. Matches follow as you type;
+// Enter applies the query, also loading commit data for the "changed"
+// filter, and ESC abandons the search.
+func (m TreeModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		cmd := m.applyQuery(true)
+		return m, cmd
+	case tea.KeyEsc:
+		m.clearSearch()
+		return m, nil
+	case tea.KeyBackspace:
+		if r := []rune(m.searchInput); len(r) > 0 {
+			m.searchInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.searchInput += " "
+	case tea.KeyRunes:
+		m.searchInput += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	m.applyQuery(false)
+	return m, nil
+}
+
+// applyQuery parses the search input and re-runs it. A query that does not
+// parse leaves the previous one in place. Commit data for the "changed"
+// filter is only fetched when fetch is set, so typing does not fire off a
+// request per keystroke.
+func (m *TreeModel) applyQuery(fetch bool) tea.Cmd {
+	q, err := parseTreeQuery(m.searchInput)
+	m.queryErr = err
+	if err != nil {
+		return nil
+	}
+	m.query = q
+
+	var cmd tea.Cmd
+	if fetch {
+		cmd = m.ensureActivity()
+	}
+	m.refilter()
+	m.jumpToMatch(0)
+	return cmd
+}
+
+// clearSearch drops the query, showing the whole tree again
+func (m *TreeModel) clearSearch() {
+	m.searching, m.searchInput, m.queryErr = false, "", nil
+	m.query = treeQuery{}
+	m.refilter()
+}
+
+// ensureActivity loads commit data reaching back as far as the "changed"
+// filter and the overlay need, unless it is already loaded or on its way
+func (m *TreeModel) ensureActivity() tea.Cmd {
+	since, ok := m.activityWindow()
+	if !ok || m.activityLoading || m.client == nil || m.activity.covers(since) {
+		return nil
+	}
+	m.activityLoading, m.activityErr = true, nil
+	return loadActivity(m.client, m.target, since, m.commitFiles)
+}
+
+// activityWindow is how far back commit data is needed, if at all
+func (m TreeModel) activityWindow() (time.Time, bool) {
+	var since time.Time
+	if m.query.ChangedDays > 0 {
+		since = m.query.changedCutoff(time.Now())
+	}
+	if m.overlay.needsActivity() && (since.IsZero() || m.windowStart.Before(since)) {
+		since = m.windowStart
+	}
+	return since, !since.IsZero()
+}
+
+// refilter works out which nodes pass the query's filters and which match
+// its text. Directories holding files that pass are expanded.
+func (m *TreeModel) refilter() {
+	m.kept = nil
+	var keep func(*FileNode) bool
+	if m.query.filtering() {
+		m.kept = map[*FileNode]bool{m.root: true}
+		m.markKept(m.root, time.Now())
+		keep = func(node *FileNode) bool { return m.kept[node] }
+	}
+
+	m.matches, m.matchPos, m.highlights = nil, 0, map[*FileNode][]int{}
+	if m.query.Text != "" {
+		m.matches = searchTree(m.root, m.query.Text, keep)
+		for _, match := range m.matches {
+			m.highlights[match.node] = match.positions
+		}
+	}
+	m.updateVisibleList()
+}
+
+// markKept records whether node passes the filters, returning the result.
+// Until commit data arrives the "changed" filter lets everything through.
+// Unloaded directories are kept so they can still be expanded.
+func (m *TreeModel) markKept(node *FileNode, now time.Time) bool {
+	kept := false
+	switch {
+	case node.Type == "dir" && !node.Loaded:
+		kept = true
+	case node.Type == "dir":
+		for _, child := range node.Children {
+			if m.markKept(child, now) {
+				kept = true
+			}
+		}
+		if kept && node != m.root {
+			node.Expanded = true
+		}
+	default:
+		kept = m.query.keepFile(node)
+		if kept && m.query.ChangedDays > 0 && m.activity != nil {
+			kept = m.activity.changedSince(node.Path, m.query.changedCutoff(now))
+		}
+	}
+	if kept {
+		m.kept[node] = true
+	}
+	return kept
+}
+
+// jumpToMatch moves the cursor to the i-th match, wrapping around, and
+// expands its ancestors so it is visible
+func (m *TreeModel) jumpToMatch(i int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.matchPos = (i%len(m.matches) + len(m.matches)) % len(m.matches)
+	node := m.matches[m.matchPos].node
+	for dir := node.parent; dir != nil; dir = dir.parent {
+		dir.Expanded = true
+	}
+	m.updateVisibleList()
+	for i, n := range m.visibleList {
+		if n == node {
+			m.cursor = i
+			break
+		}
+	}
+}
+
+// loadSubtree fetches the children of a directory cut from a truncated
+// listing. Trees are addressed by SHA, so repeat visits hit the cache.
+// Directories only implied by the paths of a truncated listing have no SHA
+// yet; theirs is looked up level by level from the nearest ancestor with one.
+func (m *TreeModel) loadSubtree(node *FileNode) tea.Cmd {
+	if m.client == nil || m.loading[node] {
+		return nil
+	}
+	var names []string
+	base := node
+	for base != nil && base.SHA == "" {
+		names = append([]string{base.Name}, names...)
+		base = base.parent
+	}
+	if base == nil {
+		return func() tea.Msg {
+			return subtreeLoadedMsg{node: node, err: fmt.Errorf("cannot load %s: the repository tree is unknown", node.Path)}
+		}
+	}
+	m.loading[node] = true
+	client, target, sha := m.client, m.target, base.SHA
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), treeFetchTimeout)
+		defer cancel()
+
+		for _, name := range names {
+			tree, err := client.GetTree(ctx, target.Owner, target.Name, sha)
+			if err != nil {
+				return subtreeLoadedMsg{node: node, err: err}
+			}
+			sha = ""
+			for _, e := range tree.Tree {
+				if e.Type == "tree" && e.Path == name {
+					sha = e.Sha
+					break
+				}
+			}
+			if sha == "" {
+				return subtreeLoadedMsg{node: node, err: fmt.Errorf("directory %s no longer exists", node.Path)}
+			}
+		}
+
+		tree, err := client.GetTree(ctx, target.Owner, target.Name, sha)
+		if err != nil {
+			return subtreeLoadedMsg{node: node, err: err}
+		}
+		return subtreeLoadedMsg{node: node, sha: sha, entries: tree.Tree}
+	}
+}
+
+// openPreview shows node in the preview pane, fetching it unless it is
+// cached or cannot be previewed
+func (m *TreeModel) openPreview(node *FileNode) tea.Cmd {
+	if p := previewFor(node, m.previews); p != nil {
+		m.preview, m.previewScroll, m.previewLoading = p, 0, ""
+		return nil
+	}
+	if m.client == nil {
+		return nil
+	}
+	m.previewLoading = node.Path
+	return loadPreview(m.client, m.target, node)
+}
+
+// previewRows is how many lines of a file fit in the preview pane
+func (m TreeModel) previewRows() int {
+	return max(m.height-8, 1)
+}
+
 func (m TreeModel) View() string {
 	if m.width == 0 || m.height == 0 {
 		return "Initializing..."
 	}
 
-	content := TitleStyle.Render("📁 REPOSITORY FILE TREE") + "\n\n"
+	header := TitleStyle.Render("📁 REPOSITORY FILE TREE") + "\n"
+	rows := m.height - 7
+	if m.truncated {
+		header += ErrorStyle.Render("⚠️  GitHub truncated this tree; directories marked + load when expanded") + "\n"
+		rows--
+	}
+	if m.err != nil {
+		header += renderError(m.err) + "\n"
+		rows--
+	}
+	if line := m.overlayLine(); line != "" {
+		header += line + "\n"
+		rows--
+	}
+	if line := m.searchLine(); line != "" {
+		header += line + "\n"
+		rows--
+	}
+	rows = max(rows, 1)
+
+	footer := "↑↓ navigate • ← → expand/collapse • Enter open • / search • o overlay • ESC back"
+	switch {
+	case m.searching:
+		footer = "Type to search • ext:go size>10k changed:30d filter • Enter apply • ESC cancel"
+	case m.preview != nil || m.previewLoading != "":
+		footer = "↑↓ navigate • Enter open • PgUp/PgDn scroll • ESC close preview"
+	case !m.query.empty():
+		footer = "↑↓ navigate • n/N next/previous match • / edit search • ESC clear search"
+	}
+
+	// Box borders and padding take 10 columns
+	treeWidth := m.width - 10
+	var body string
+	if m.preview == nil && m.previewLoading == "" {
+		body = m.treeView(rows, treeWidth)
+	} else {
+		treeWidth = (m.width - 10) * 2 / 5
+		previewWidth := m.width - 10 - treeWidth - 3
+		body = lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(treeWidth).Render(m.treeView(rows, treeWidth)),
+			SubtleStyle.Render(strings.TrimSuffix(strings.Repeat(" │\n", rows), "\n")),
+			lipgloss.NewStyle().PaddingLeft(1).Render(m.previewView(rows, previewWidth)),
+		)
+	}
 
-	// Display visible nodes
-	startIdx := m.cursor - (m.height - 5) / 2
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Left, lipgloss.Top,
+		BoxStyle.Render(header+"\n"+body+"\n\n"+SubtleStyle.Render(footer)),
+	)
+}
+
+// searchLine describes the search being typed or applied
+func (m TreeModel) searchLine() string {
+	if !m.searching && m.query.empty() {
+		return ""
+	}
+
+	line := InputStyle.Render("/ " + m.searchInput)
+	if m.searching {
+		line += InputStyle.Render("█")
+	}
+	var status []string
+	if m.query.Text != "" {
+		if len(m.matches) == 0 {
+			status = append(status, "no matches")
+		} else {
+			status = append(status, fmt.Sprintf("match %d of %d", m.matchPos+1, len(m.matches)))
+		}
+	}
+	if m.query.ChangedDays > 0 {
+		switch {
+		case m.activityLoading:
+			status = append(status, "loading commit history…")
+		case m.activity == nil && m.searching:
+			status = append(status, "press Enter to load commit history")
+		case m.activity != nil && m.activity.Partial:
+			status = append(status, fmt.Sprintf("changes from the latest %d commits", m.activity.Commits))
+		}
+	}
+	if len(status) > 0 {
+		line += "  " + SubtleStyle.Render(strings.Join(status, " • "))
+	}
+
+	switch {
+	case m.queryErr != nil:
+		line += "  " + ErrorStyle.Render(m.queryErr.Error())
+	case m.activityErr != nil:
+		line += "  " + ErrorStyle.Render(m.activityErr.Error())
+	}
+	return line
+}
+
+// treeView renders the visible part of the tree, keeping the cursor centred
+func (m TreeModel) treeView(rows, width int) string {
+	startIdx := m.cursor - rows/2
 	if startIdx < 0 {
 		startIdx = 0
 	}
-	endIdx := startIdx + (m.height - 5)
+	endIdx := startIdx + rows
 	if endIdx > len(m.visibleList) {
 		endIdx = len(m.visibleList)
 	}
 
+	now := time.Now()
+	var lines []string
 	for i := startIdx; i < endIdx; i++ {
 		node := m.visibleList[i]
-		indent := m.getIndent(node)
+		indent := strings.Repeat("  ", m.visibleDepth[i])
 
 		icon := "📄"
-		if node.Type == "dir" {
+		switch node.Type {
+		case "dir":
 			icon = "📁"
-			if node.Expanded && len(node.Children) > 0 {
+			if node.Expanded {
 				icon = "📂"
 			}
+		case "submodule":
+			icon = "🔗"
 		}
 
 		prefix := "  "
@@ -150,95 +632,184 @@ func (m TreeModel) View() string {
 			style = SelectedStyle
 		}
 
-		line := fmt.Sprintf("%s%s%s %s", prefix, indent, icon, node.Name)
-		content += style.Render(line) + "\n"
-	}
-
-	footer := SubtleStyle.Render("↑↓ navigate • ← → expand/collapse • Enter select • ESC back")
-	content += "\n" + footer
+		size := ""
+		switch {
+		case node.Type == "submodule":
+			size = "submodule"
+		case m.loading[node]:
+			size = "loading…"
+		case node.Type == "dir" && !node.Loaded:
+			size = formatBytes(node.Size) + "+"
+		default:
+			size = formatBytes(node.Size)
+		}
 
-	return lipgloss.Place(
-		m.width, m.height,
-		lipgloss.Left, lipgloss.Top,
-		BoxStyle.Render(content),
-	)
-}
+		cell, heat := m.overlayCell(node, now)
+		if heat >= 0 && i != m.cursor {
+			style = heatStyles[heat]
+		}
 
-func (m TreeModel) getIndent(node *FileNode) string {
-	depth := m.getNodeDepth(m.root, node)
-	indent := ""
-	for i := 0; i < depth; i++ {
-		indent += "  "
+		line := style.Render(fmt.Sprintf("%s%s%s ", prefix, indent, icon)) +
+			highlightName(node.Name, m.highlights[node], style.Render, matchStyle.Render) +
+			"  " + SubtleStyle.Render(size)
+		if heat >= 0 {
+			line += "  " + heatStyles[heat].Render(cell)
+		}
+		lines = append(lines, ansi.Truncate(line, width, "…"))
 	}
-	return indent
+	return strings.Join(lines, "\n")
 }
 
-func (m TreeModel) getNodeDepth(parent *FileNode, target *FileNode) int {
-	if parent == target {
-		return 0
+// previewView renders the preview pane
+func (m TreeModel) previewView(rows, width int) string {
+	if m.previewLoading != "" {
+		return SubtleStyle.Render("Loading " + m.previewLoading + "…")
 	}
 
-	for _, child := range parent.Children {
-		if child == target {
-			return 1
+	p := m.preview
+	lines := []string{TitleStyle.Render(p.Path)}
+	rows--
+
+	switch {
+	case p.Err != nil:
+		lines = append(lines, renderError(p.Err))
+	case p.Kind == previewText:
+		end := min(m.previewScroll+rows, len(p.Lines))
+		for _, line := range p.Lines[m.previewScroll:end] {
+			lines = append(lines, ansi.Truncate(line, width, "…"))
+		}
+		if len(p.Lines) > rows {
+			lines[0] += SubtleStyle.Render(fmt.Sprintf("  lines %d-%d of %d", m.previewScroll+1, end, len(p.Lines)))
 		}
-		depth := m.getNodeDepth(child, target)
-		if depth >= 0 {
-			return depth + 1
+	default:
+		lines = append(lines, "")
+		for _, meta := range p.Meta {
+			lines = append(lines, SubtleStyle.Render(meta))
 		}
 	}
-	return -1
+	return strings.Join(lines, "\n")
 }
 
-// BuildFileTree creates a file tree from repository content
+// BuildFileTree creates the directory hierarchy from the recursive tree
+// listing, with directory sizes summed and directories sorted first. When
+// the listing was truncated every directory is marked unloaded, since any
+// of them may be missing entries.
 func BuildFileTree(result AnalysisResult) *FileNode {
 	root := &FileNode{
-		Name:     result.Repo.Name,
+		Name:     "repository",
 		Type:     "dir",
-		Path:     "/",
 		Children: []*FileNode{},
+		Loaded:   !result.TreeTruncated,
+	}
+	if result.Repo != nil {
+		// Any tree-ish works for fetching the root level
+		root.Name, root.SHA = result.Repo.Name, result.Repo.DefaultBranch
 	}
 
-	// Add directories based on languages
-	dirs := []string{"src", "pkg", "internal", "cmd", "test", "docs", "config", "scripts", "build", "web", "api"}
-	for _, dir := range dirs {
-		root.Children = append(root.Children, &FileNode{
-			Name:     dir,
-			Type:     "dir",
-			Path:     "/" + dir,
-			Children: []*FileNode{},
-		})
-	}
-
-	// Add files based on actual languages detected
-	for lang := range result.Languages {
-		ext := ".txt"
-		switch lang {
-		case "Go": ext = ".go"
-		case "Python": ext = ".py"
-		case "JavaScript": ext = ".js"
-		case "TypeScript": ext = ".ts"
-		case "Rust": ext = ".rs"
-		case "Java": ext = ".java"
-		}
-		root.Children = append(root.Children, &FileNode{
-			Name: "main" + ext,
-			Type: "file",
-			Path: "/src/main" + ext,
-			Size: 2048,
-		})
-	}
-
-	// Add common files in root
-	sampleFiles := []string{"README.md", "LICENSE", "go.mod", "go.sum", ".gitignore", "Makefile", "Dockerfile"}
-	for _, file := range sampleFiles {
-		root.Children = append(root.Children, &FileNode{
-			Name: file,
-			Type: "file",
-			Path: "/" + file,
-			Size: 1024,
-		})
+	dirs := map[string]*FileNode{"": root}
+	// dirFor returns the node for path, creating missing ancestors
+	var dirFor func(path string) *FileNode
+	dirFor = func(path string) *FileNode {
+		if dir, ok := dirs[path]; ok {
+			return dir
+		}
+		parentPath, base := "", path
+		if i := strings.LastIndex(path, "/"); i >= 0 {
+			parentPath, base = path[:i], path[i+1:]
+		}
+		parent := dirFor(parentPath)
+		dir := &FileNode{Name: base, Type: "dir", Path: path, Loaded: !result.TreeTruncated, parent: parent}
+		parent.Children = append(parent.Children, dir)
+		dirs[path] = dir
+		return dir
+	}
+
+	for _, e := range result.FileTree {
+		if e.Type == "tree" {
+			dirFor(e.Path).SHA = e.Sha
+			continue
+		}
+
+		parentPath, base := "", e.Path
+		if i := strings.LastIndex(e.Path, "/"); i >= 0 {
+			parentPath, base = e.Path[:i], e.Path[i+1:]
+		}
+		parent := dirFor(parentPath)
+		parent.Children = append(parent.Children, newLeaf(parent, base, e))
 	}
 
+	finishTree(root)
 	return root
 }
+
+// newLeaf creates the node for a blob or submodule entry
+func newLeaf(parent *FileNode, name string, e github.TreeEntry) *FileNode {
+	node := &FileNode{Name: name, Type: "file", Path: e.Path, SHA: e.Sha, Size: int64(e.Size), Loaded: true, parent: parent}
+	if e.Type == "commit" {
+		node.Type = "submodule"
+	}
+	return node
+}
+
+// mergeSubtree replaces a directory's children with one fetched level,
+// keeping the nodes of subdirectories already known so their expansion
+// state and loaded entries survive
+func mergeSubtree(dir *FileNode, entries []github.TreeEntry) {
+	known := map[string]*FileNode{}
+	for _, child := range dir.Children {
+		if child.Type == "dir" {
+			known[child.Name] = child
+		}
+	}
+
+	children := make([]*FileNode, 0, len(entries))
+	for _, e := range entries {
+		// One-level listings give names relative to the directory
+		full := e
+		if dir.Path != "" {
+			full.Path = dir.Path + "/" + e.Path
+		}
+
+		if e.Type != "tree" {
+			children = append(children, newLeaf(dir, e.Path, full))
+			continue
+		}
+		child := known[e.Path]
+		if child == nil {
+			child = &FileNode{Name: e.Path, Type: "dir", Path: full.Path, parent: dir}
+		}
+		child.SHA = e.Sha
+		children = append(children, child)
+	}
+
+	dir.Children = children
+	dir.Loaded = true
+
+	root := dir
+	for root.parent != nil {
+		root = root.parent
+	}
+	finishTree(root)
+}
+
+// finishTree sorts children dirs-first and sums directory sizes
+func finishTree(node *FileNode) int64 {
+	if node.Type != "dir" {
+		return node.Size
+	}
+
+	sort.Slice(node.Children, func(i, j int) bool {
+		a, b := node.Children[i], node.Children[j]
+		if (a.Type == "dir") != (b.Type == "dir") {
+			return a.Type == "dir"
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	var total int64
+	for _, child := range node.Children {
+		total += finishTree(child)
+	}
+	node.Size = total
+	return total
+}
@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func findNode(root *FileNode, path string) *FileNode {
+	if root.Path == path {
+		return root
+	}
+	for _, child := range root.Children {
+		if n := findNode(child, path); n != nil {
+			return n
+		}
+	}
+	return nil
+}
+
+func childNames(node *FileNode) []string {
+	var names []string
+	for _, c := range node.Children {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestBuildFileTree(t *testing.T) {
+	root := BuildFileTree(AnalysisResult{
+		Repo: &github.Repo{Name: "r", DefaultBranch: "main"},
+		FileTree: []github.TreeEntry{
+			{Path: "README.md", Type: "blob", Size: 10, Sha: "b1"},
+			{Path: "cmd", Type: "tree", Sha: "t1"},
+			{Path: "cmd/main.go", Type: "blob", Size: 5, Sha: "b2"},
+			{Path: "lib", Type: "commit", Sha: "s1"},
+			{Path: "Zeta.txt", Type: "blob", Size: 1},
+			{Path: "api", Type: "tree", Sha: "t2"},
+		},
+	})
+
+	if root.Name != "r" || root.SHA != "main" || !root.Loaded {
+		t.Errorf("root = %+v", root)
+	}
+	if got := strings.Join(childNames(root), ","); got != "api,cmd,lib,README.md,Zeta.txt" {
+		t.Errorf("children = %s, want directories first then case-insensitive names", got)
+	}
+	if root.Size != 16 {
+		t.Errorf("root size = %d, want 16", root.Size)
+	}
+	cmd := findNode(root, "cmd")
+	if cmd.SHA != "t1" || cmd.Size != 5 || cmd.parent != root || !cmd.Loaded {
+		t.Errorf("cmd = %+v", cmd)
+	}
+	if lib := findNode(root, "lib"); lib.Type != "submodule" {
+		t.Errorf("lib type = %q, want submodule", lib.Type)
+	}
+}
+
+func TestBuildFileTreeTruncated(t *testing.T) {
+	// A truncated listing may name files whose directories it cut
+	root := BuildFileTree(AnalysisResult{
+		Repo:          &github.Repo{Name: "r", DefaultBranch: "main"},
+		TreeTruncated: true,
+		FileTree: []github.TreeEntry{
+			{Path: "a/b/c.go", Type: "blob", Size: 7, Sha: "b1"},
+			{Path: "a/d.go", Type: "blob", Size: 3, Sha: "b2"},
+		},
+	})
+
+	a, b := findNode(root, "a"), findNode(root, "a/b")
+	if a == nil || b == nil {
+		t.Fatalf("implicit directories missing: %v", childNames(root))
+	}
+	for _, dir := range []*FileNode{root, a, b} {
+		if dir.Loaded {
+			t.Errorf("%q is marked loaded in a truncated listing", dir.Path)
+		}
+	}
+	if a.SHA != "" || b.SHA != "" {
+		t.Errorf("implicit directories have SHAs %q, %q", a.SHA, b.SHA)
+	}
+	if b.parent != a || a.parent != root || b.Name != "b" {
+		t.Error("implicit directories are not linked to their parents")
+	}
+	if a.Size != 10 || b.Size != 7 {
+		t.Errorf("sizes = %d, %d, want 10, 7", a.Size, b.Size)
+	}
+}
+
+func TestMergeSubtree(t *testing.T) {
+	root := BuildFileTree(AnalysisResult{
+		Repo:          &github.Repo{Name: "r", DefaultBranch: "main"},
+		TreeTruncated: true,
+		FileTree: []github.TreeEntry{
+			{Path: "src", Type: "tree", Sha: "t1"},
+			{Path: "src/old.go", Type: "blob", Size: 100},
+			{Path: "src/pkg/x.go", Type: "blob", Size: 1},
+		},
+	})
+	src, pkg := findNode(root, "src"), findNode(root, "src/pkg")
+	pkg.Expanded = true
+
+	mergeSubtree(src, []github.TreeEntry{
+		{Path: "pkg", Type: "tree", Sha: "t2"},
+		{Path: "new.go", Type: "blob", Size: 4, Sha: "b1"},
+		{Path: "vendor", Type: "tree", Sha: "t3"},
+		{Path: "mod", Type: "commit", Sha: "s1"},
+	})
+
+	if !src.Loaded {
+		t.Error("merged directory is not loaded")
+	}
+	if got := strings.Join(childNames(src), ","); got != "pkg,vendor,mod,new.go" {
+		t.Errorf("children = %s", got)
+	}
+	// Known subdirectories keep their node, expansion and entries
+	if findNode(root, "src/pkg") != pkg || !pkg.Expanded || pkg.SHA != "t2" || len(pkg.Children) != 1 {
+		t.Errorf("pkg = %+v", pkg)
+	}
+	vendor := findNode(root, "src/vendor")
+	if vendor == nil || vendor.Loaded || vendor.SHA != "t3" || vendor.parent != src {
+		t.Errorf("new directory = %+v", vendor)
+	}
+	if n := findNode(root, "src/new.go"); n == nil || n.SHA != "b1" {
+		t.Error("file names were not made relative to the root")
+	}
+	if findNode(root, "src/old.go") != nil {
+		t.Error("entries missing from the fetched level were kept")
+	}
+	// Sizes are recomputed up to the root
+	if src.Size != 5 || root.Size != 5 {
+		t.Errorf("sizes = %d, %d, want 5, 5", src.Size, root.Size)
+	}
+}
+
+// treeServer serves one-level listings by tree SHA
+func treeServer(t *testing.T, trees map[string][]github.TreeEntry) (*github.Client, *[]string) {
+	t.Helper()
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sha := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		requested = append(requested, sha)
+		entries, ok := trees[sha]
+		if !ok {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(github.TreeResponse{Sha: sha, Tree: entries})
+	}))
+	t.Cleanup(srv.Close)
+	return github.NewClient(github.WithBaseURL(srv.URL), github.WithToken(""), github.WithRetryPolicy(github.RetryPolicy{})), &requested
+}
+
+func TestLoadSubtreeImplicitDirectory(t *testing.T) {
+	client, requested := treeServer(t, map[string][]github.TreeEntry{
+		"main": {{Path: "a", Type: "tree", Sha: "ta"}},
+		"ta":   {{Path: "b", Type: "tree", Sha: "tb"}, {Path: "d.go", Type: "blob"}},
+		"tb":   {{Path: "c.go", Type: "blob", Sha: "bc"}, {Path: "e.go", Type: "blob", Sha: "be"}},
+	})
+	result := &AnalysisResult{
+		Repo:          &github.Repo{Name: "r", FullName: "o/r", DefaultBranch: "main"},
+		TreeTruncated: true,
+		FileTree:      []github.TreeEntry{{Path: "a/b/c.go", Type: "blob", Sha: "bc"}},
+	}
+	m := NewTreeModel(result, client)
+	b := findNode(m.root, "a/b")
+
+	cmd := m.loadSubtree(b)
+	if cmd == nil {
+		t.Fatal("no command to load a directory without a SHA")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(TreeModel)
+
+	if m.err != nil {
+		t.Fatalf("err = %v", m.err)
+	}
+	if got := strings.Join(*requested, ","); got != "main,ta,tb" {
+		t.Errorf("requested trees %s, want the lookup from the root", got)
+	}
+	if !b.Loaded || b.SHA != "tb" || strings.Join(childNames(b), ",") != "c.go,e.go" {
+		t.Errorf("a/b = %+v with %v", b, childNames(b))
+	}
+	if m.loading[b] {
+		t.Error("directory still marked loading")
+	}
+}
+
+func TestLoadSubtreeReportsMissingDirectory(t *testing.T) {
+	client, _ := treeServer(t, map[string][]github.TreeEntry{
+		"main": {{Path: "other", Type: "tree", Sha: "to"}},
+	})
+	m := NewTreeModel(&AnalysisResult{
+		Repo:          &github.Repo{Name: "r", FullName: "o/r", DefaultBranch: "main"},
+		TreeTruncated: true,
+		FileTree:      []github.TreeEntry{{Path: "gone/x.go", Type: "blob"}},
+	}, client)
+	gone := findNode(m.root, "gone")
+
+	updated, _ := m.Update(m.loadSubtree(gone)())
+	m = updated.(TreeModel)
+	if m.err == nil || !strings.Contains(m.err.Error(), "gone") {
+		t.Errorf("err = %v, want the missing directory reported", m.err)
+	}
+	if gone.Loaded {
+		t.Error("missing directory marked loaded")
+	}
+
+	// Without a repository there is no tree to start from
+	m = NewTreeModel(&AnalysisResult{TreeTruncated: true, FileTree: []github.TreeEntry{{Path: "x/y.go", Type: "blob"}}}, client)
+	updated, _ = m.Update(m.loadSubtree(findNode(m.root, "x"))())
+	if updated.(TreeModel).err == nil {
+		t.Error("loading without a repository tree reported nothing")
+	}
+}
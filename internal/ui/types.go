@@ -1,22 +1,74 @@
 package ui
 
-import "github.com/agnivo988/Repo-lyzer/internal/github"
+import (
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
 
 type AnalysisResult struct {
 	Repo          *github.Repo
 	Commits       []github.Commit
+	CommitFiles   []github.Commit `json:"-"` // Sampled commits with their file lists
 	Contributors  []github.Contributor
 	FileTree      []github.TreeEntry
+	TreeTruncated bool
 	Languages     map[string]int
+	Releases      []github.Release
+	ReleaseStats  analyzer.ReleaseStats
+	IssueHealth   analyzer.IssueHealth
+	PRHealth      analyzer.PRHealth
+	Security      analyzer.SecurityReport
+	Files         analyzer.FileStats
 	HealthScore   int
+	Health        analyzer.HealthReport
 	BusFactor     int
 	BusRisk       string
+	TruckFactor   analyzer.TruckFactor
 	MaturityScore int
 	MaturityLevel string
+	Maturity      analyzer.Maturity
+	RateLimit     *github.RateLimit        // Budget seen on the last API response, nil if unknown
+	FetchErrors   map[analysis.Fetch]error `json:"-"` // Fetches that failed; their data is missing
 }
 
-// CompareResult holds analysis data for two repositories
+// CompareResult holds analysis data for the compared repositories, in the
+// order they were entered, and how they rank
 type CompareResult struct {
-	Repo1 AnalysisResult
-	Repo2 AnalysisResult
+	Repos      []AnalysisResult
+	Comparison analysis.Comparison
+}
+
+// newAnalysisResult adapts an analysis run for the dashboard
+func newAnalysisResult(r *analysis.Result, client *github.Client) AnalysisResult {
+	var rateLimit *github.RateLimit
+	if budget, ok := client.RateBudget(); ok {
+		rateLimit = budget
+	}
+
+	return AnalysisResult{
+		Repo:          r.Repo,
+		Commits:       r.Commits,
+		CommitFiles:   r.CommitFiles,
+		Contributors:  r.Contributors,
+		FileTree:      r.FileTree,
+		TreeTruncated: r.TreeTruncated,
+		Languages:     r.Languages,
+		Releases:      r.Releases,
+		ReleaseStats:  r.ReleaseStats,
+		IssueHealth:   r.IssueHealth,
+		PRHealth:      r.PRHealth,
+		Security:      r.Security,
+		Files:         r.Files,
+		HealthScore:   r.HealthScore,
+		Health:        r.Health,
+		BusFactor:     r.BusFactor,
+		BusRisk:       r.BusRisk,
+		TruckFactor:   r.TruckFactor,
+		MaturityScore: r.MaturityScore,
+		MaturityLevel: r.MaturityLevel,
+		Maturity:      r.Maturity,
+		RateLimit:     rateLimit,
+		FetchErrors:   r.Errors,
+	}
 }